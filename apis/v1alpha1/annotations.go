@@ -1,6 +1,46 @@
 package v1alpha1
 
+import "strings"
+
 const (
 	AnnotationExposedSecret = "secretdetection.lvlcn-t.dev/exposed-secret"
 	AnnotationAppliedPolicy = "secretdetection.lvlcn-t.dev/applied-policy"
+	// AnnotationKey holds the original ConfigMap key reported by an ExposedSecret,
+	// since the key is sanitized and hashed in the resource name.
+	AnnotationKey = "secretdetection.lvlcn-t.dev/key"
+	// AnnotationException holds the name of the SecretException that excepted the finding reported by an ExposedSecret.
+	// The "Ignore" action of an excepted ExposedSecret is therefore not mistaken for a user override.
+	AnnotationException = "secretdetection.lvlcn-t.dev/exception"
+	// AnnotationRemediate is set to "true" on admission on ConfigMaps using generateName, whose secrets can only be
+	// remediated once they are stored, see the "Remediate" admission mode. It is removed by the reconciliation
+	// and ignored unless the namespace's effective policy uses the "Remediate" admission mode.
+	AnnotationRemediate = "secretdetection.lvlcn-t.dev/remediate"
+	// AnnotationSkip opts a ConfigMap out of scanning if it is set to "true".
+	AnnotationSkip = "secretdetection.lvlcn-t.dev/skip"
+)
+
+const (
+	// LabelExposedSecret is set on remediated Secrets and points to the ExposedSecret that created them.
+	LabelExposedSecret = "secretdetection.lvlcn-t.dev/exposed-secret"
+	// LabelFingerprint is set on ExposedSecrets and holds the fingerprint of the reported value, see [NewFingerprint].
+	LabelFingerprint = "secretdetection.lvlcn-t.dev/fingerprint"
+	// LabelManagedBy is the well-known label marking the resources managed by the operator.
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	// ManagedBy is the value of [LabelManagedBy] for the resources managed by the operator.
+	ManagedBy = "secret-detection-operator"
 )
+
+// FinalizerRetainFindings protects a ConfigMap from being deleted before its
+// ExposedSecrets were released, if the policy retains findings, see [RetentionRetain].
+const FinalizerRetainFindings = "secretdetection.lvlcn-t.dev/retain-findings"
+
+// DefaultPolicySource is the value of [AnnotationAppliedPolicy]
+// if the operator's default scan policy was applied.
+const DefaultPolicySource = "default"
+
+// PolicySource returns the value of [AnnotationAppliedPolicy] for
+// a policy of the given kind and name, e.g. "ClusterScanPolicy/team-defaults".
+// Multiple names are used for merged policies, e.g. "ScanPolicy/a,b".
+func PolicySource(kind string, names ...string) string {
+	return kind + "/" + strings.Join(names, ",")
+}
@@ -0,0 +1,99 @@
+package v1alpha1
+
+import (
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ClusterScanPolicySpec defines scanning configuration for a set of namespaces
+type ClusterScanPolicySpec struct {
+	ScanPolicySpec `json:",inline"`
+
+	// NamespaceSelector selects the namespaces the policy applies to by their labels.
+	// An empty or missing selector matches all namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Namespaces restricts the policy to namespaces whose name matches one of the given glob patterns,
+	// e.g. "team-*". If both namespaces and namespaceSelector are set, a namespace has to match both.
+	// An empty list matches all namespaces.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Priority decides which policy is applied if multiple ClusterScanPolicies match a namespace.
+	// The policy with the highest priority wins; ties are broken by the policy name.
+	// +kubebuilder:default=0
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=csp,scope=Cluster
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterScanPolicy defines scanning configuration for all namespaces matching its selector.
+// A ScanPolicy in a namespace always takes precedence over ClusterScanPolicies.
+type ClusterScanPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterScanPolicySpec `json:"spec,omitempty"`
+	Status ScanPolicyStatus      `json:"status,omitempty"`
+}
+
+// Matches reports whether the policy applies to the namespace with the given name and labels.
+func (p *ClusterScanPolicy) Matches(namespace string, nsLabels map[string]string) (bool, error) {
+	if len(p.Spec.Namespaces) > 0 && !matchesAny(p.Spec.Namespaces, namespace) {
+		return false, nil
+	}
+
+	if p.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(nsLabels)), nil
+}
+
+// ScanPolicyFor returns the [ScanPolicy] the cluster policy resolves to in the given namespace.
+func (p *ClusterScanPolicy) ScanPolicyFor(namespace string) *ScanPolicy {
+	return &ScanPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: path.Join(APIGroup, APIVersion),
+			Kind:       "ScanPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       p.Name,
+			Namespace:  namespace,
+			Generation: p.Generation,
+			UID:        p.UID,
+		},
+		Spec: *p.Spec.ScanPolicySpec.DeepCopy(),
+	}
+}
+
+// matchesAny reports whether name matches any of the glob patterns.
+// Invalid patterns never match.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterScanPolicyList contains a list of ClusterScanPolicy
+type ClusterScanPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterScanPolicy `json:"items"`
+}
@@ -0,0 +1,143 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Condition types of the operator's resources.
+const (
+	// ConditionReady is true if the resource was processed by the controller.
+	ConditionReady = "Ready"
+	// ConditionRemediated is true if the secret reported by an [ExposedSecret] was moved to a Secret.
+	ConditionRemediated = "Remediated"
+	// ConditionDegraded is true if the resource needs attention, e.g. the secret
+	// of an [ExposedSecret] is still exposed or a policy has invalid rules.
+	ConditionDegraded = "Degraded"
+	// ConditionRulesValid is true if all custom scanner rules of a policy are valid.
+	ConditionRulesValid = "RulesValid"
+)
+
+// Condition reasons of the operator's resources.
+const (
+	// ReasonReconciled is the reason of [ConditionReady] if the resource was processed.
+	ReasonReconciled = "Reconciled"
+	// ReasonSecretExposed is the reason of [ConditionDegraded] if the secret is still part of the ConfigMap.
+	ReasonSecretExposed = "SecretExposed"
+	// ReasonValid is the reason of [ConditionRulesValid] if all rules are valid.
+	ReasonValid = "Valid"
+	// ReasonInvalidRules is the reason of [ConditionRulesValid] and [ConditionDegraded] if some rules are invalid.
+	ReasonInvalidRules = "InvalidRules"
+	// ReasonApplied is the reason of [ConditionReady] if a policy was applied to its namespace.
+	ReasonApplied = "Applied"
+	// ReasonPepperMissing is the reason of [ConditionDegraded] if the policy uses a keyed hash algorithm
+	// but the operator has no pepper configured.
+	ReasonPepperMissing = "PepperMissing"
+)
+
+// SetConditions derives the conditions of the [ExposedSecret] from its phase.
+// The reason of a condition that isn't true is the current phase.
+func (s *ExposedSecretStatus) SetConditions(generation int64) {
+	phase := string(s.Phase)
+	if phase == "" {
+		phase = "Unknown"
+	}
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:               ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonReconciled,
+		Message:            s.Message,
+		ObservedGeneration: generation,
+	})
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:               ConditionRemediated,
+		Status:             conditionStatus(s.Phase == PhaseRemediated),
+		Reason:             phase,
+		Message:            s.Message,
+		ObservedGeneration: generation,
+	})
+
+	degraded := metav1.Condition{
+		Type:               ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             phase,
+		ObservedGeneration: generation,
+	}
+	if s.Phase == PhaseDetected {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = ReasonSecretExposed
+		degraded.Message = fmt.Sprintf("Secret is still exposed at %q", s.Location())
+	}
+	meta.SetStatusCondition(&s.Conditions, degraded)
+}
+
+// SetConditions validates the custom scanner rules of the policy and updates its conditions and message.
+// Invalid rules are skipped by the scanner, so the policy is still applied but degraded.
+func (s *ScanPolicyStatus) SetConditions(generation int64, spec *ScanPolicySpec) {
+	s.ObservedGeneration = generation
+	s.Message = "Policy applied"
+
+	rulesValid := metav1.Condition{
+		Type:               ConditionRulesValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonValid,
+		ObservedGeneration: generation,
+	}
+	degraded := metav1.Condition{
+		Type:               ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonValid,
+		ObservedGeneration: generation,
+	}
+	if errs := spec.GitleaksConfig.Validate(field.NewPath("spec", "gitleaksConfig")); len(errs) > 0 {
+		s.Message = fmt.Sprintf("Policy applied, %d invalid gitleaks rules are skipped", len(errs))
+		rulesValid.Status = metav1.ConditionFalse
+		rulesValid.Reason = ReasonInvalidRules
+		rulesValid.Message = errs.ToAggregate().Error()
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = ReasonInvalidRules
+		degraded.Message = s.Message
+	}
+
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:               ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonApplied,
+		Message:            s.Message,
+		ObservedGeneration: generation,
+	})
+	meta.SetStatusCondition(&s.Conditions, rulesValid)
+	meta.SetStatusCondition(&s.Conditions, degraded)
+}
+
+// SetPepperMissing marks the policy as degraded because its keyed hash algorithm has no pepper.
+// The values of its findings are masked instead, see [AlgorithmMasked].
+// It must be called after [ScanPolicyStatus.SetConditions].
+func (s *ScanPolicyStatus) SetPepperMissing(generation int64, algorithm HashAlgorithm) {
+	s.Message = fmt.Sprintf("Policy applied, hash algorithm %s requires a pepper, values are masked instead", algorithm)
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:               ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonApplied,
+		Message:            s.Message,
+		ObservedGeneration: generation,
+	})
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:               ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonPepperMissing,
+		Message:            s.Message,
+		ObservedGeneration: generation,
+	})
+}
+
+// conditionStatus converts a boolean into a [metav1.ConditionStatus].
+func conditionStatus(ok bool) metav1.ConditionStatus {
+	if ok {
+		return metav1.ConditionTrue
+	}
+	return metav1.ConditionFalse
+}
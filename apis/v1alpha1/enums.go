@@ -1,10 +1,13 @@
 package v1alpha1
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/base64"
 	"encoding/hex"
+	"fmt"
+	"strings"
 
 	"github.com/lvlcn-t/secret-detection-operator/scanners"
 )
@@ -25,6 +28,9 @@ const (
 	ActionAutoRemediate Action = "AutoRemediate"
 	// ActionIgnore indicates that the secret should be ignored
 	ActionIgnore Action = "Ignore"
+	// ActionRevert indicates that a remediated secret should be restored into the ConfigMap.
+	// It can only be set on an ExposedSecret, not as the default action of a policy.
+	ActionRevert Action = "Revert"
 
 	// DefaultAction is the default action to take when a secret is detected.
 	DefaultAction Action = ActionReportOnly
@@ -34,6 +40,42 @@ const (
 // a secret detected in a ConfigMap.
 type Severity = scanners.Severity
 
+// AdmissionMode represents how ConfigMaps containing
+// secrets are handled when they are created or updated.
+type AdmissionMode string
+
+// String returns the string representation of the admission mode.
+func (m AdmissionMode) String() string {
+	return string(m)
+}
+
+const (
+	// AdmissionEnforce denies ConfigMaps containing secrets
+	AdmissionEnforce AdmissionMode = "Enforce"
+	// AdmissionRemediate moves secrets from ConfigMaps to Secrets before the ConfigMaps are stored
+	AdmissionRemediate AdmissionMode = "Remediate"
+	// AdmissionWarn admits ConfigMaps containing secrets but returns a warning to the client
+	AdmissionWarn AdmissionMode = "Warn"
+	// AdmissionOff doesn't scan ConfigMaps on admission
+	AdmissionOff AdmissionMode = "Off"
+)
+
+// Retention represents what happens to the findings
+// of a ConfigMap when the ConfigMap is deleted.
+type Retention string
+
+// String returns the string representation of the retention.
+func (r Retention) String() string {
+	return string(r)
+}
+
+const (
+	// RetentionDelete garbage collects the findings together with their ConfigMap
+	RetentionDelete Retention = "Delete"
+	// RetentionRetain keeps the findings for auditing after their ConfigMap was deleted
+	RetentionRetain Retention = "Retain"
+)
+
 // Phase represents the current phase of an
 // ExposedSecret in the reconciliation process.
 type Phase string
@@ -50,6 +92,31 @@ const (
 	PhaseRemediated Phase = "Remediated"
 	// PhaseIgnored means the finding was explicitly ignored
 	PhaseIgnored Phase = "Ignored"
+	// PhaseReverted means the remediation was reverted and the secret was restored into the ConfigMap
+	PhaseReverted Phase = "Reverted"
+	// PhaseResolved means the secret is no longer part of the ConfigMap, e.g. because the key or the ConfigMap was deleted
+	PhaseResolved Phase = "Resolved"
+)
+
+// Exposed reports whether an ExposedSecret in the phase still exposes the value in its ConfigMap.
+func (p Phase) Exposed() bool {
+	return p == PhaseDetected || p == PhaseIgnored || p == PhaseReverted
+}
+
+// KeySource represents the field of a ConfigMap
+// that holds the key in which a secret was detected.
+type KeySource string
+
+// String returns the string representation of the key source.
+func (ks KeySource) String() string {
+	return string(ks)
+}
+
+const (
+	// SourceData means the key is part of the ConfigMap's data
+	SourceData KeySource = "data"
+	// SourceBinaryData means the key is part of the ConfigMap's binaryData
+	SourceBinaryData KeySource = "binaryData"
 )
 
 // ScannerName represents the name of a secret scanner.
@@ -65,20 +132,65 @@ func (ha HashAlgorithm) String() string {
 }
 
 const (
-	// AlgorithmNone is the no hashing algorithm.
+	// AlgorithmNone is the no hashing algorithm. The value is only base64 encoded,
+	// so it must be enabled explicitly in the operator's configuration.
 	AlgorithmNone HashAlgorithm = "none"
+	// AlgorithmMasked keeps a short preview of the value and its length, see [Mask].
+	AlgorithmMasked HashAlgorithm = "masked"
 	// AlgorithmSHA256 is the SHA-256 hashing algorithm.
 	AlgorithmSHA256 HashAlgorithm = "sha256"
 	// AlgorithmSHA512 is the SHA-512 hashing algorithm.
 	AlgorithmSHA512 HashAlgorithm = "sha512"
+	// AlgorithmHMACSHA256 is the HMAC-SHA-256 hashing algorithm keyed by a [Pepper].
+	AlgorithmHMACSHA256 HashAlgorithm = "hmac-sha256"
+	// AlgorithmHMACSHA512 is the HMAC-SHA-512 hashing algorithm keyed by a [Pepper].
+	AlgorithmHMACSHA512 HashAlgorithm = "hmac-sha512"
 )
 
+// Pepper is the secret key of the HMAC hashing algorithms.
+// Unlike a salt, it is shared by all hashes and never stored next to them,
+// so low-entropy secrets can't be brute-forced from the reported hashes alone.
+type Pepper struct {
+	// ID identifies the key. It is part of the hash prefix, so hashes of different keys can be told apart after a rotation.
+	ID string
+	// Key is the secret key.
+	Key []byte
+}
+
+// Keyed reports whether the hashing algorithm requires a [Pepper].
+func (ha HashAlgorithm) Keyed() bool {
+	return ha == AlgorithmHMACSHA256 || ha == AlgorithmHMACSHA512
+}
+
+// HashWithPepper hashes the given secret value using the hashing algorithm and the pepper.
+// Keyed algorithms return the value prefixed with the algorithm name and the pepper's ID,
+// e.g. "hmac-sha256:2024-01:<hex>". Without a pepper, they return "<unsupported>".
+// Unkeyed algorithms ignore the pepper, see [HashAlgorithm.Hash].
+func (ha HashAlgorithm) HashWithPepper(secret string, pepper *Pepper) string {
+	if !ha.Keyed() {
+		return ha.Hash(secret)
+	}
+	if pepper == nil {
+		return "<unsupported>"
+	}
+
+	h := hmac.New(sha256.New, pepper.Key)
+	if ha == AlgorithmHMACSHA512 {
+		h = hmac.New(sha512.New, pepper.Key)
+	}
+	h.Write([]byte(secret))
+	return ha.String() + ":" + pepper.ID + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
 // Hash hashes the given secret value using the hashing algorithm.
 // It returns the hashed value as a string prefixed with the algorithm name.
+// Keyed algorithms need a pepper, see [HashAlgorithm.HashWithPepper].
 func (ha HashAlgorithm) Hash(secret string) string {
 	switch ha {
 	case AlgorithmNone:
 		return base64.StdEncoding.EncodeToString([]byte(secret))
+	case AlgorithmMasked:
+		return Mask(secret)
 	case AlgorithmSHA256:
 		hash := sha256.Sum256([]byte(secret))
 		return "sha256:" + hex.EncodeToString(hash[:])
@@ -89,3 +201,52 @@ func (ha HashAlgorithm) Hash(secret string) string {
 		return "<unsupported>"
 	}
 }
+
+const (
+	// maskMinLen is the minimum number of characters of a value revealing any of them when masked.
+	maskMinLen = 12
+	// maskReveal is the maximum number of characters revealed at the start and the end of a masked value.
+	maskReveal = 2
+)
+
+// Mask returns a preview of the secret value that is safe to report, prefixed with "masked:".
+// The number of revealed characters scales with the length: values shorter than [maskMinLen] characters
+// only reveal their length, longer values reveal one character at their start and end for every
+// [maskMinLen] characters, but at most [maskReveal], e.g. "masked:gh****…f3 (40 chars)".
+func Mask(secret string) string {
+	r := []rune(secret)
+	preview := "****…"
+	if n := min(len(r)/maskMinLen, maskReveal); n > 0 {
+		preview = string(r[:n]) + preview + string(r[len(r)-n:])
+	}
+	return fmt.Sprintf("%s:%s (%d chars)", AlgorithmMasked, preview, len(r))
+}
+
+// HashAlgorithmOf returns the hashing algorithm of a value hashed by [HashAlgorithm.Hash]
+// or [HashAlgorithm.HashWithPepper], derived from its prefix.
+// Values without a known prefix are base64 encoded by [AlgorithmNone].
+func HashAlgorithmOf(hashed string) HashAlgorithm {
+	prefix, _, ok := strings.Cut(hashed, ":")
+	if !ok {
+		return AlgorithmNone
+	}
+	switch ha := HashAlgorithm(prefix); ha {
+	case AlgorithmMasked, AlgorithmSHA256, AlgorithmSHA512, AlgorithmHMACSHA256, AlgorithmHMACSHA512:
+		return ha
+	default:
+		return AlgorithmNone
+	}
+}
+
+// PepperIDOf returns the ID of the pepper a value was hashed with by [HashAlgorithm.HashWithPepper].
+// It returns an empty string if the value wasn't hashed by a keyed algorithm.
+func PepperIDOf(hashed string) string {
+	if !HashAlgorithmOf(hashed).Keyed() {
+		return ""
+	}
+	parts := strings.SplitN(hashed, ":", 3)
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[1]
+}
@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		want   string
+	}{
+		{name: "empty", secret: "", want: "masked:****… (0 chars)"},
+		{name: "short password", secret: "hunter2", want: "masked:****… (7 chars)"},
+		{name: "just below the minimum", secret: "abcdefghijk", want: "masked:****… (11 chars)"},
+		{name: "minimum", secret: "abcdefghijkl", want: "masked:a****…l (12 chars)"},
+		{name: "16 characters", secret: "abcdefghijklmnop", want: "masked:a****…p (16 chars)"},
+		{name: "24 characters", secret: "abcdefghijklmnopqrstuvwx", want: "masked:ab****…wx (24 chars)"},
+		{name: "long token", secret: "ghp_" + strings.Repeat("x", 34) + "f3", want: "masked:gh****…f3 (40 chars)"},
+		{name: "multi-byte characters", secret: strings.Repeat("ä", 12), want: "masked:ä****…ä (12 chars)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Mask(tt.secret))
+		})
+	}
+}
+
+func TestMask_RevealsAtMostFourCharacters(t *testing.T) {
+	for n := range 100 {
+		masked := Mask(strings.Repeat("x", n))
+		// At most a sixth of the value and never more than four characters are revealed.
+		require.LessOrEqual(t, strings.Count(masked, "x"), min(4, n/6), "length %d: %s", n, masked)
+	}
+}
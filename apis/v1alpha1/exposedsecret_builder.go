@@ -1,8 +1,13 @@
 package v1alpha1
 
 import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"path"
+	"slices"
+	"strings"
 
 	"github.com/lvlcn-t/secret-detection-operator/apis/validation"
 	"github.com/lvlcn-t/secret-detection-operator/scanners"
@@ -17,10 +22,18 @@ type ExposedSecretBuilder struct {
 	existingAction Action
 	// override is true if the user actually set that action (vs leaving it at the policy default)
 	override bool
+	// existingPhase is the phase of the resource if it already existed
+	existingPhase Phase
+	// existingException is the name of the SecretException that excepted the existing resource
+	existingException string
 
-	policy   *ScanPolicy
-	severity scanners.Severity
-	hashAlgo HashAlgorithm
+	configMap *corev1.ConfigMap
+	policy    *ScanPolicy
+	severity  scanners.Severity
+	hashAlgo  HashAlgorithm
+	pepper    *Pepper
+	// secret is the secret matched by the scanner inside the detected value, see [ExposedSecretBuilder.Fingerprint].
+	secret string
 }
 
 func NewExposedSecretBuilder(cfg *corev1.ConfigMap, exposedKey string) *ExposedSecretBuilder {
@@ -33,7 +46,7 @@ func NewExposedSecretBuilder(cfg *corev1.ConfigMap, exposedKey string) *ExposedS
 			ObjectMeta: metav1.ObjectMeta{
 				Name:        NewExposedSecretName(cfg, exposedKey),
 				Namespace:   cfg.Namespace,
-				Annotations: map[string]string{},
+				Annotations: map[string]string{AnnotationKey: exposedKey},
 			},
 			Spec: ExposedSecretSpec{
 				Action:   DefaultAction,
@@ -43,6 +56,7 @@ func NewExposedSecretBuilder(cfg *corev1.ConfigMap, exposedKey string) *ExposedS
 			Status: ExposedSecretStatus{
 				ConfigMapReference: ConfigMapReference{Name: cfg.Name},
 				Key:                exposedKey,
+				Source:             SourceData,
 				Scanner:            "",
 				DetectedValue:      cfg.Data[exposedKey],
 				Phase:              PhaseDetected,
@@ -51,7 +65,8 @@ func NewExposedSecretBuilder(cfg *corev1.ConfigMap, exposedKey string) *ExposedS
 				Message:            fmt.Sprintf("Secret detected in ConfigMap %q for key %q", cfg.Name, exposedKey),
 			},
 		},
-		hashAlgo: AlgorithmSHA256,
+		configMap: cfg,
+		hashAlgo:  AlgorithmSHA256,
 	}
 }
 
@@ -63,6 +78,16 @@ func (b *ExposedSecretBuilder) Override() bool {
 	return b.override
 }
 
+// ExistingPhase returns the phase of the existing resource, or an empty phase if it didn't exist.
+func (b *ExposedSecretBuilder) ExistingPhase() Phase {
+	return b.existingPhase
+}
+
+// ExistingException returns the name of the [SecretException] that excepted the existing resource, if any.
+func (b *ExposedSecretBuilder) ExistingException() string {
+	return b.existingException
+}
+
 func (b *ExposedSecretBuilder) WithAction(act Action) *ExposedSecretBuilder {
 	b.Spec.Action = act
 	return b
@@ -81,12 +106,47 @@ func (b *ExposedSecretBuilder) WithPolicy(policy *ScanPolicy) *ExposedSecretBuil
 	return b
 }
 
+// WithPepper sets the key of the keyed hashing algorithms, see [HashAlgorithm.HashWithPepper].
+func (b *ExposedSecretBuilder) WithPepper(pepper *Pepper) *ExposedSecretBuilder {
+	b.pepper = pepper
+	return b
+}
+
+// WithException records the name of the [SecretException] that excepted the finding.
+func (b *ExposedSecretBuilder) WithException(name string) *ExposedSecretBuilder {
+	b.Annotations[AnnotationException] = name
+	return b
+}
+
+// Fingerprint returns the fingerprint of the secret matched by the scanner, see [NewFingerprint].
+// The surrounding content of the detected value is ignored, so copies of a secret in different files share a fingerprint.
+// Values without a reported secret are fingerprinted as a whole.
+// It is computed on the first call, so the findings, the value's location and the pepper must be set before.
+func (b *ExposedSecretBuilder) Fingerprint() string {
+	if b.Status.Fingerprint == "" {
+		b.Status.Fingerprint = NewFingerprint(cmp.Or(b.secret, b.Status.DetectedValue), b.pepper)
+	}
+	return b.Status.Fingerprint
+}
+
+// WithPolicySource records which policy was applied, see [PolicySource].
+func (b *ExposedSecretBuilder) WithPolicySource(source string) *ExposedSecretBuilder {
+	b.Annotations[AnnotationAppliedPolicy] = source
+	return b
+}
+
 func (b *ExposedSecretBuilder) WithExisting(es *ExposedSecret) *ExposedSecretBuilder {
-	if es.Spec.Action != DefaultAction {
+	excepted := es.Annotations[AnnotationException] != "" && es.Spec.Action == ActionIgnore
+	if es.Spec.Action != DefaultAction && !excepted {
 		b.existingAction = es.Spec.Action
 		b.override = true
 	}
+	b.existingPhase = es.Status.Phase
+	b.existingException = es.Annotations[AnnotationException]
 	b.Spec.Notes = es.Spec.Notes
+	b.Spec.DeleteSecretOnRevert = es.Spec.DeleteSecretOnRevert
+	b.Status.RewiredWorkloads = es.Status.RewiredWorkloads
+	b.Status.Conditions = es.Status.Conditions
 	return b
 }
 
@@ -96,11 +156,48 @@ func (b *ExposedSecretBuilder) WithSeverity(s scanners.Severity) *ExposedSecretB
 	return b
 }
 
+// WithFindings records the scanner findings for the exposed value.
+// The most severe finding determines the reported rule and line.
+func (b *ExposedSecretBuilder) WithFindings(findings []scanners.Finding) *ExposedSecretBuilder {
+	b.Status.MatchCount = len(findings)
+	if p := scanners.Primary(findings); p != nil {
+		b.Status.RuleID = p.RuleID
+		b.Status.Line = p.StartLine
+		b.secret = cmp.Or(p.Secret, p.Match)
+	}
+	return b
+}
+
 func (b *ExposedSecretBuilder) WithMessage(message string) *ExposedSecretBuilder {
 	b.Status.Message = message
 	return b
 }
 
+// WithBinaryData marks the exposed key as part of the ConfigMap's binaryData.
+// The innerPath is the path of the file inside the binary container that held the secret
+// and becomes part of the resource name, so every file gets its own [ExposedSecret].
+func (b *ExposedSecretBuilder) WithBinaryData(innerPath string, value []byte) *ExposedSecretBuilder {
+	b.Status.Source = SourceBinaryData
+	b.Status.InnerPath = innerPath
+	b.Status.DetectedValue = string(value)
+	return b.locate()
+}
+
+// WithPath sets the JSONPath of the secret inside a structured value and the value found at that path.
+// The path becomes part of the resource name, so every nested finding gets its own [ExposedSecret].
+func (b *ExposedSecretBuilder) WithPath(p, value string) *ExposedSecretBuilder {
+	b.Status.Path = p
+	b.Status.DetectedValue = value
+	return b.locate()
+}
+
+// locate updates the name and message of the [ExposedSecret] to match its location in the ConfigMap.
+func (b *ExposedSecretBuilder) locate() *ExposedSecretBuilder {
+	b.Name = NewExposedSecretName(b.configMap, b.Status.Key, b.Status.InnerPath, b.Status.Path)
+	b.Status.Message = fmt.Sprintf("Secret detected in ConfigMap %q at %q", b.configMap.Name, b.Status.Location())
+	return b
+}
+
 func (b *ExposedSecretBuilder) WithRemediated(secret *corev1.Secret) *ExposedSecretBuilder {
 	b.Status.CreatedSecretRef = &SecretReference{Name: secret.Name}
 	b.Spec.Action = ActionAutoRemediate
@@ -108,14 +205,71 @@ func (b *ExposedSecretBuilder) WithRemediated(secret *corev1.Secret) *ExposedSec
 	return b
 }
 
+// WithRewiredWorkloads records the workloads patched to read the key from the created Secret.
+// Workloads rewired during previous reconciliations are kept.
+func (b *ExposedSecretBuilder) WithRewiredWorkloads(refs ...WorkloadReference) *ExposedSecretBuilder {
+	workloads := append(slices.Clone(b.Status.RewiredWorkloads), refs...)
+	slices.SortFunc(workloads, func(x, y WorkloadReference) int {
+		return cmp.Or(cmp.Compare(x.Kind, y.Kind), cmp.Compare(x.Name, y.Name))
+	})
+	b.Status.RewiredWorkloads = slices.Compact(workloads)
+	return b
+}
+
 func (b *ExposedSecretBuilder) Build() *ExposedSecret {
 	b.Status.LastUpdateTime = metav1.Now()
-	b.Status.DetectedValue = b.hashAlgo.Hash(b.Status.DetectedValue)
+	if fp := b.Fingerprint(); fp != "" {
+		if b.Labels == nil {
+			b.Labels = map[string]string{}
+		}
+		b.Labels[LabelFingerprint] = fp
+	}
+	b.Status.DetectedValue = b.hashAlgo.HashWithPepper(b.Status.DetectedValue, b.pepper)
 	return b.ExposedSecret
 }
 
+// Location returns a human-readable location of the secret inside the ConfigMap,
+// e.g. "application.yaml:$.spring.datasource.password" or "bundle.tar.gz!config/app.yaml:$.password".
+func (s *ExposedSecretStatus) Location() string {
+	loc := s.Key
+	if s.InnerPath != "" {
+		loc += "!" + s.InnerPath
+	}
+	if s.Path != "" {
+		loc += ":" + s.Path
+	}
+	return loc
+}
+
+// nameHashLen is the number of hex characters of the hash suffix of an [ExposedSecret] name.
+const nameHashLen = 10
+
 // NewExposedSecretName creates a new name for the ExposedSecret based on
 // the ConfigMap name and the key that contains the exposed secret.
-func NewExposedSecretName(cfgMap *corev1.ConfigMap, key string) string {
-	return fmt.Sprintf("%s-%s", cfgMap.Name, validation.MakeDNS1123Subdomain(key))
+// Optional sub-paths (e.g. the file inside a binary container) are appended to the name.
+//
+// Keys are sanitized to be valid in a resource name, so different keys (e.g. "db.password"
+// and "db_password") could map to the same readable name. The name therefore ends with a
+// short hash of the original ConfigMap name, key and sub-paths, and the readable part is
+// truncated to leave room for it.
+func NewExposedSecretName(cfgMap *corev1.ConfigMap, key string, subPaths ...string) string {
+	name := fmt.Sprintf("%s-%s", cfgMap.Name, validation.MakeDNS1123Subdomain(key))
+	h := sha256.New()
+	h.Write([]byte(cfgMap.Name))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	for _, sub := range subPaths {
+		if sub == "" {
+			continue
+		}
+		name = fmt.Sprintf("%s-%s", name, validation.MakeDNS1123Subdomain(sub))
+		h.Write([]byte{0})
+		h.Write([]byte(sub))
+	}
+
+	const budget = validation.MaxNameLength - nameHashLen - 1
+	if len(name) > budget {
+		name = strings.TrimRight(name[:budget], "-.")
+	}
+	return name + "-" + hex.EncodeToString(h.Sum(nil))[:nameHashLen]
 }
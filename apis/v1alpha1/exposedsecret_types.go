@@ -19,13 +19,31 @@ type SecretReference struct {
 	Name string `json:"name"`
 }
 
+// WorkloadReference is a reference to a workload in the namespace of the ExposedSecret.
+type WorkloadReference struct {
+	// Kind of the referenced workload
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;CronJob
+	Kind string `json:"kind"`
+
+	// Name of the referenced workload
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
 // ExposedSecretSpec defines user intent and desired handling behavior
 type ExposedSecretSpec struct {
-	// Action defines the desired response: "ReportOnly", "AutoRemediate", "Ignore"
-	// +kubebuilder:validation:Enum=ReportOnly;AutoRemediate;Ignore
+	// Action defines the desired response: "ReportOnly", "AutoRemediate", "Ignore", "Revert"
+	// Revert restores a remediated secret from the created Secret into the ConfigMap
+	// and keeps the operator from remediating it again.
+	// +kubebuilder:validation:Enum=ReportOnly;AutoRemediate;Ignore;Revert
 	// +kubebuilder:default=ReportOnly
 	Action Action `json:"action,omitempty"`
 
+	// DeleteSecretOnRevert deletes the created Secret once the secret was restored into the ConfigMap.
+	// The Secret is kept if workloads were rewired to read from it.
+	// +optional
+	DeleteSecretOnRevert bool `json:"deleteSecretOnRevert,omitempty"`
+
 	// Severity indicates how serious the secret exposure is
 	// +kubebuilder:validation:Enum=Unknown;Low;Medium;High;Critical
 	// +kubebuilder:default=Medium
@@ -45,20 +63,65 @@ type ExposedSecretStatus struct {
 	// +kubebuilder:validation:MinLength=1
 	Key string `json:"key"`
 
+	// Source is the ConfigMap field holding the key: "data" or "binaryData".
+	// +kubebuilder:validation:Enum=data;binaryData
+	// +kubebuilder:default=data
+	Source KeySource `json:"source,omitempty"`
+
+	// InnerPath is the path of the file inside a binary container (gzip, tar, zip, keystore)
+	// that held the secret. Nested containers are separated by '!'.
+	// It is only set if the secret was found in a binaryData key.
+	// +optional
+	InnerPath string `json:"innerPath,omitempty"`
+
+	// Path is the JSONPath of the secret inside a structured value (YAML, JSON, .properties, .env, INI, TOML),
+	// e.g. "$.spring.datasource.password". It is empty if the whole value was reported.
+	// +optional
+	Path string `json:"path,omitempty"`
+
 	// Scanner indicates the tool that detected the secret.
 	Scanner ScannerName `json:"scanner,omitempty"`
 
+	// RuleID is the identifier of the scanner rule that detected the secret.
+	// If multiple rules matched, it is the rule of the most severe match.
+	// +optional
+	RuleID string `json:"ruleID,omitempty"`
+
+	// Line is the 1-based line of the value (or file) where the secret was detected.
+	// +optional
+	Line int `json:"line,omitempty"`
+
+	// MatchCount is the number of matches the scanner reported for the value.
+	// +optional
+	MatchCount int `json:"matchCount,omitempty"`
+
 	// DetectedValue is the found secret value as a hash.
 	DetectedValue string `json:"detectedValue,omitempty"`
 
+	// Fingerprint is a stable fingerprint of the secret matched by the scanner, keyed by the operator's pepper.
+	// ExposedSecrets reporting the same secret share the fingerprint, regardless of the surrounding content,
+	// and are aggregated by the SecretFingerprint of that name. It is only set if a pepper is configured.
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+
 	// CreatedSecretRef points to the Secret created to store the migrated key/value.
 	// This will only be set if the action is "AutoRemediate".
 	CreatedSecretRef *SecretReference `json:"createdSecretRef,omitempty"`
 
-	// Phase is the current status: "Detected", "Remediated", "Ignored"
-	// +kubebuilder:validation:Enum=Detected;Remediated;Ignored
+	// RewiredWorkloads lists the workloads patched to read the key from the created Secret instead of the ConfigMap.
+	// This will only be set if workload rewiring is enabled by the ScanPolicy.
+	// +optional
+	RewiredWorkloads []WorkloadReference `json:"rewiredWorkloads,omitempty"`
+
+	// Phase is the current status: "Detected", "Remediated", "Ignored", "Reverted", "Resolved"
+	// +kubebuilder:validation:Enum=Detected;Remediated;Ignored;Reverted;Resolved
 	Phase Phase `json:"phase,omitempty"`
 
+	// ResolvedTime is the time the secret was found to be no longer part of the ConfigMap.
+	// It is only set in the "Resolved" phase.
+	// +optional
+	ResolvedTime *metav1.Time `json:"resolvedTime,omitempty"`
+
 	// Message provides additional details about the status.
 	Message string `json:"message,omitempty"`
 
@@ -67,6 +130,12 @@ type ExposedSecretStatus struct {
 
 	// ObservedGeneration is the last generation seen by the controller
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the ExposedSecret's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -31,6 +31,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ExposedSecretList{},
 		&ScanPolicy{},
 		&ScanPolicyList{},
+		&ClusterScanPolicy{},
+		&ClusterScanPolicyList{},
+		&SecretFingerprint{},
+		&SecretFingerprintList{},
+		&SecretException{},
+		&SecretExceptionList{},
 	)
 	metav1.AddToGroupVersion(scheme, GroupVersion)
 	return nil
@@ -0,0 +1,174 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// globChars are the metacharacters of the glob patterns supported by [path.Match].
+const globChars = `*?[\`
+
+// MatchPattern reports whether the name matches the pattern of [ScanPolicySpec.ExcludedKeys]
+// or [ScanPolicySpec.ExcludedConfigMaps]: a regular expression enclosed in slashes that has
+// to match the whole name, e.g. "/^public_.+$/", a glob pattern, e.g. "*.crt", or the exact name.
+// Names containing glob metacharacters, e.g. "list[0]", always match themselves.
+func MatchPattern(pattern, name string) (bool, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+	return p.match(name), nil
+}
+
+// pattern is a compiled pattern of [ScanPolicySpec.ExcludedKeys] or [ScanPolicySpec.ExcludedConfigMaps].
+type pattern struct {
+	// source is the pattern as defined in the policy.
+	source string
+	// re is the regular expression of a pattern enclosed in slashes.
+	re *regexp.Regexp
+	// glob is true if the source is a valid glob pattern with metacharacters.
+	glob bool
+}
+
+// compilePattern compiles the pattern, see [MatchPattern].
+// Only regular expressions can be invalid; invalid glob patterns are compared exactly.
+func compilePattern(source string) (pattern, error) {
+	if expr, ok := regexPattern(source); ok {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return pattern{}, err
+		}
+		return pattern{source: source, re: re}, nil
+	}
+	if !strings.ContainsAny(source, globChars) {
+		return pattern{source: source}, nil
+	}
+	_, err := path.Match(source, "")
+	return pattern{source: source, glob: !errors.Is(err, path.ErrBadPattern)}, nil
+}
+
+// match reports whether the name matches the pattern.
+func (p pattern) match(name string) bool {
+	switch {
+	case p.re != nil:
+		return p.re.MatchString(name)
+	case name == p.source:
+		return true
+	case p.glob:
+		ok, _ := path.Match(p.source, name)
+		return ok
+	default:
+		return false
+	}
+}
+
+// regexPattern returns the regular expression of a pattern enclosed in slashes.
+func regexPattern(pattern string) (string, bool) {
+	if len(pattern) < 2 || !strings.HasPrefix(pattern, "/") || !strings.HasSuffix(pattern, "/") {
+		return "", false
+	}
+	return pattern[1 : len(pattern)-1], true
+}
+
+// compilePatterns compiles the patterns, skipping invalid ones since they never match.
+func compilePatterns(sources []string) []pattern {
+	patterns := make([]pattern, 0, len(sources))
+	for _, source := range sources {
+		if p, err := compilePattern(source); err == nil {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// firstMatch returns the first pattern matching the name, or an empty string if none does.
+func firstMatch(patterns []pattern, name string) string {
+	for _, p := range patterns {
+		if p.match(name) {
+			return p.source
+		}
+	}
+	return ""
+}
+
+// Exclusions are the compiled exclusions of a [ScanPolicySpec], see [ScanPolicySpec.Exclusions].
+// Compiling them once avoids compiling the regular expressions for every key.
+// +kubebuilder:object:generate=false
+type Exclusions struct {
+	keys       []pattern
+	configMaps []pattern
+	selectors  []labels.Selector
+}
+
+// Exclusions compiles the patterns and selectors excluding keys and ConfigMaps from scanning.
+// Invalid patterns and selectors never match.
+func (s *ScanPolicySpec) Exclusions() *Exclusions {
+	e := &Exclusions{
+		keys:       compilePatterns(s.ExcludedKeys),
+		configMaps: compilePatterns(s.ExcludedConfigMaps),
+	}
+	for i := range s.ExcludedConfigMapSelectors {
+		selector, err := metav1.LabelSelectorAsSelector(&s.ExcludedConfigMapSelectors[i])
+		if err != nil || selector.Empty() {
+			continue
+		}
+		e.selectors = append(e.selectors, selector)
+	}
+	return e
+}
+
+// KeyPattern returns the pattern of [ScanPolicySpec.ExcludedKeys] matching the key,
+// or an empty string if the key isn't excluded.
+func (e *Exclusions) KeyPattern(key string) string {
+	return firstMatch(e.keys, key)
+}
+
+// ConfigMapPattern returns the pattern of [ScanPolicySpec.ExcludedConfigMaps] matching
+// the ConfigMap's name, or an empty string if the ConfigMap isn't excluded by its name.
+func (e *Exclusions) ConfigMapPattern(name string) string {
+	return firstMatch(e.configMaps, name)
+}
+
+// ConfigMapSelector returns the selector of [ScanPolicySpec.ExcludedConfigMapSelectors] matching
+// the ConfigMap's labels, or an empty string if the ConfigMap isn't excluded by its labels.
+func (e *Exclusions) ConfigMapSelector(cmLabels map[string]string) string {
+	for _, selector := range e.selectors {
+		if selector.Matches(labels.Set(cmLabels)) {
+			return selector.String()
+		}
+	}
+	return ""
+}
+
+// ValidateExclusions validates the patterns and selectors excluding keys and ConfigMaps from scanning.
+func (s *ScanPolicySpec) ValidateExclusions(specPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	validate := func(fldPath *field.Path, patterns []string) {
+		for i, pattern := range patterns {
+			if _, err := MatchPattern(pattern, ""); err != nil {
+				errs = append(errs, field.Invalid(fldPath.Index(i), pattern, fmt.Sprintf("invalid pattern: %v", err)))
+			}
+		}
+	}
+	validate(specPath.Child("excludedKeys"), s.ExcludedKeys)
+	validate(specPath.Child("excludedConfigMaps"), s.ExcludedConfigMaps)
+
+	for i := range s.ExcludedConfigMapSelectors {
+		fldPath := specPath.Child("excludedConfigMapSelectors").Index(i)
+		selector, err := metav1.LabelSelectorAsSelector(&s.ExcludedConfigMapSelectors[i])
+		switch {
+		case err != nil:
+			errs = append(errs, field.Invalid(fldPath, s.ExcludedConfigMapSelectors[i], fmt.Sprintf("invalid selector: %v", err)))
+		case selector.Empty():
+			errs = append(errs, field.Invalid(fldPath, s.ExcludedConfigMapSelectors[i], "empty selector would exclude all ConfigMaps"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,80 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		key     string
+		want    bool
+		wantErr bool
+	}{
+		{name: "exact", pattern: "password", key: "password", want: true},
+		{name: "exact mismatch", pattern: "password", key: "password2"},
+		{name: "glob", pattern: "*.crt", key: "ca.crt", want: true},
+		{name: "glob mismatch", pattern: "*.crt", key: "ca.key"},
+		{name: "regex", pattern: "/^public_.+$/", key: "public_token", want: true},
+		{name: "regex matches the whole key", pattern: "/public/", key: "public_token"},
+		{name: "invalid regex", pattern: "/(unclosed/", key: "(unclosed", wantErr: true},
+		{name: "key with glob characters matches itself", pattern: "list[0]", key: "list[0]", want: true},
+		{name: "key with glob characters still matches as glob", pattern: "list[0]", key: "list0", want: true},
+		{name: "key with question mark matches itself", pattern: "ready?", key: "ready?", want: true},
+		{name: "escaped key matches itself", pattern: `a\b`, key: `a\b`, want: true},
+		{name: "invalid glob matches exactly", pattern: "list[0", key: "list[0", want: true},
+		{name: "invalid glob doesn't match others", pattern: "list[0", key: "list0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchPattern(tt.pattern, tt.key)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExclusions(t *testing.T) {
+	spec := &ScanPolicySpec{
+		ExcludedKeys:       []string{"/(unclosed/", "list[0", "*.crt", "/^public_.+$/"},
+		ExcludedConfigMaps: []string{"kube-*.crt"},
+		ExcludedConfigMapSelectors: []metav1.LabelSelector{
+			{},
+			{MatchLabels: map[string]string{"grafana_dashboard": "1"}},
+		},
+	}
+	e := spec.Exclusions()
+
+	require.Empty(t, e.KeyPattern("(unclosed"), "invalid regular expressions never match")
+	require.Equal(t, "list[0", e.KeyPattern("list[0"))
+	require.Equal(t, "*.crt", e.KeyPattern("ca.crt"))
+	require.Equal(t, "/^public_.+$/", e.KeyPattern("public_token"))
+	require.Empty(t, e.KeyPattern("token"))
+
+	require.Equal(t, "kube-*.crt", e.ConfigMapPattern("kube-root-ca.crt"))
+	require.Empty(t, e.ConfigMapPattern("cm"))
+
+	require.Equal(t, "grafana_dashboard=1", e.ConfigMapSelector(map[string]string{"grafana_dashboard": "1"}))
+	require.Empty(t, e.ConfigMapSelector(nil), "empty selectors never match")
+}
+
+func TestValidateExclusions(t *testing.T) {
+	spec := &ScanPolicySpec{
+		ExcludedKeys:       []string{"/(unclosed/", "list[0", "*.crt"},
+		ExcludedConfigMaps: []string{"/[/"},
+	}
+	errs := spec.ValidateExclusions(field.NewPath("spec"))
+	require.Len(t, errs, 2)
+	require.Equal(t, "spec.excludedKeys[0]", errs[0].Field)
+	require.Equal(t, "spec.excludedConfigMaps[0]", errs[1].Field)
+}
@@ -0,0 +1,98 @@
+package v1alpha1
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/lvlcn-t/secret-detection-operator/scanners/gitleaks"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// actionPrecedence lists the actions from the strongest to the weakest.
+var actionPrecedence = []Action{ActionAutoRemediate, ActionReportOnly, ActionIgnore}
+
+// admissionPrecedence lists the admission modes from the strongest to the weakest.
+var admissionPrecedence = []AdmissionMode{AdmissionEnforce, AdmissionRemediate, AdmissionWarn, AdmissionOff}
+
+// retentionPrecedence lists the finding retentions from the strongest to the weakest.
+var retentionPrecedence = []Retention{RetentionRetain, RetentionDelete}
+
+// hashPrecedence lists the hashing algorithms from the strongest to the weakest.
+var hashPrecedence = []HashAlgorithm{AlgorithmHMACSHA512, AlgorithmHMACSHA256, AlgorithmSHA512, AlgorithmSHA256, AlgorithmMasked, AlgorithmNone}
+
+// MergeScanPolicies merges the specs of the given policies into the effective spec applied to their namespace.
+// The policies are merged in the order of their names, so the result doesn't depend on the order they were listed in:
+//
+//   - ExcludedKeys is the union of all excluded keys.
+//   - ExcludedConfigMaps and ExcludedConfigMapSelectors are the unions of all excluded ConfigMaps.
+//   - MinSeverity is the strictest, i.e. lowest, severity.
+//   - Action follows the precedence AutoRemediate > ReportOnly > Ignore.
+//   - EnableConfigMapMutation is enabled if any policy enables it.
+//   - EnableWorkloadRewiring is enabled if any policy enables it.
+//   - HashAlgorithm follows the precedence hmac-sha512 > hmac-sha256 > sha512 > sha256 > masked > none.
+//   - AdmissionMode follows the precedence Enforce > Remediate > Warn > Off.
+//   - FindingRetention follows the precedence Retain > Delete.
+//   - Scanner is taken from the first policy that sets one.
+//   - GitleaksConfig rules and allowlists are merged, see [gitleaks.MergeConfigs].
+func MergeScanPolicies(policies ...ScanPolicy) ScanPolicySpec {
+	sorted := slices.Clone(policies)
+	slices.SortFunc(sorted, func(a, b ScanPolicy) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	var spec ScanPolicySpec
+	var configs []*GitleaksConfig
+	for i := range sorted {
+		s := &sorted[i].Spec
+		spec.ExcludedKeys = append(spec.ExcludedKeys, s.ExcludedKeys...)
+		spec.ExcludedConfigMaps = append(spec.ExcludedConfigMaps, s.ExcludedConfigMaps...)
+		spec.ExcludedConfigMapSelectors = append(spec.ExcludedConfigMapSelectors, s.ExcludedConfigMapSelectors...)
+		if s.MinSeverity.Int() > 0 && (spec.MinSeverity.Int() == 0 || s.MinSeverity.Int() < spec.MinSeverity.Int()) {
+			spec.MinSeverity = s.MinSeverity
+		}
+		spec.Action = strongest(actionPrecedence, spec.Action, s.Action)
+		spec.HashAlgorithm = strongest(hashPrecedence, spec.HashAlgorithm, s.HashAlgorithm)
+		spec.AdmissionMode = strongest(admissionPrecedence, spec.AdmissionMode, s.AdmissionMode)
+		spec.FindingRetention = strongest(retentionPrecedence, spec.FindingRetention, s.FindingRetention)
+		spec.EnableConfigMapMutation = spec.EnableConfigMapMutation || s.EnableConfigMapMutation
+		spec.EnableWorkloadRewiring = spec.EnableWorkloadRewiring || s.EnableWorkloadRewiring
+		if spec.Scanner == "" {
+			spec.Scanner = s.Scanner
+		}
+		configs = append(configs, s.GitleaksConfig)
+	}
+
+	slices.Sort(spec.ExcludedKeys)
+	spec.ExcludedKeys = slices.Compact(spec.ExcludedKeys)
+	slices.Sort(spec.ExcludedConfigMaps)
+	spec.ExcludedConfigMaps = slices.Compact(spec.ExcludedConfigMaps)
+	spec.ExcludedConfigMapSelectors = compactSelectors(spec.ExcludedConfigMapSelectors)
+	spec.GitleaksConfig = gitleaks.MergeConfigs(configs...)
+	return spec
+}
+
+// strongest returns the value that comes first in the precedence list.
+// Values that aren't part of the list are only returned if the other value isn't either.
+func strongest[T comparable](precedence []T, a, b T) T {
+	ia, ib := slices.Index(precedence, a), slices.Index(precedence, b)
+	switch {
+	case ia < 0:
+		return b
+	case ib < 0:
+		return a
+	case ib < ia:
+		return b
+	default:
+		return a
+	}
+}
+
+// compactSelectors sorts the label selectors and removes duplicates.
+func compactSelectors(selectors []metav1.LabelSelector) []metav1.LabelSelector {
+	slices.SortStableFunc(selectors, func(a, b metav1.LabelSelector) int {
+		return cmp.Compare(metav1.FormatLabelSelector(&a), metav1.FormatLabelSelector(&b))
+	})
+	return slices.CompactFunc(selectors, func(a, b metav1.LabelSelector) bool {
+		return metav1.FormatLabelSelector(&a) == metav1.FormatLabelSelector(&b)
+	})
+}
@@ -19,23 +19,62 @@ type ScanPolicySpec struct {
 
 	// ExcludedKeys defines a list of ConfigMap keys to ignore during scanning.
 	// This allows safe-listing non-sensitive values that may otherwise trigger false positives.
+	// Entries are glob patterns, e.g. "*.crt", or regular expressions enclosed in slashes, e.g. "/^public_.+$/",
+	// that have to match the whole key. Keys always match themselves, and entries without glob characters
+	// or with an invalid glob pattern only match exactly.
 	// +optional
 	ExcludedKeys []string `json:"excludedKeys,omitempty"`
 
+	// ExcludedConfigMaps are patterns of the names of ConfigMaps that are skipped entirely, e.g. "kube-root-ca.crt".
+	// They support the same syntax as ExcludedKeys.
+	// +optional
+	ExcludedConfigMaps []string `json:"excludedConfigMaps,omitempty"`
+
+	// ExcludedConfigMapSelectors skip all ConfigMaps whose labels match any of the selectors,
+	// e.g. the dashboards generated by a Helm chart.
+	// +optional
+	ExcludedConfigMapSelectors []metav1.LabelSelector `json:"excludedConfigMapSelectors,omitempty"`
+
 	// EnableConfigMapMutation allows the operator to delete secret-like keys from ConfigMaps.
 	// +kubebuilder:default=false
 	EnableConfigMapMutation bool `json:"enableConfigMapMutation,omitempty"`
 
+	// EnableWorkloadRewiring allows the operator to patch Deployments, StatefulSets, DaemonSets and CronJobs
+	// referencing a remediated ConfigMap key, so they read the key from the created Secret instead.
+	// It only takes effect if the key is removed from the ConfigMap, i.e. if EnableConfigMapMutation is enabled
+	// or the key is remediated on admission.
+	// +kubebuilder:default=false
+	EnableWorkloadRewiring bool `json:"enableWorkloadRewiring,omitempty"`
+
 	// Scanner defines which detection engine to use for identifying secrets.
 	// +kubebuilder:validation:Enum=Gitleaks;gitleaks
 	// +kubebuilder:default=Gitleaks
 	Scanner ScannerName `json:"scanner,omitempty"`
 
 	// HashAlgorithm defines how secret values are hashed before reporting.
-	// +kubebuilder:validation:Enum=none;sha256;sha512
-	// +kubebuilder:default=none
+	// The HMAC algorithms are keyed by the pepper of the operator's configuration.
+	// Masked keeps a short preview of the value and its length.
+	// None reports the base64 encoded plaintext and must be allowed in the operator's configuration,
+	// otherwise the value is masked.
+	// +kubebuilder:validation:Enum=none;masked;sha256;sha512;hmac-sha256;hmac-sha512
+	// +kubebuilder:default=masked
 	HashAlgorithm HashAlgorithm `json:"hashAlgorithm,omitempty"`
 
+	// AdmissionMode defines how ConfigMaps containing secrets are handled when they are created or updated.
+	// Enforce denies the request, Remediate moves the secrets to Secrets before the ConfigMap is stored,
+	// Warn admits it with a warning listing the offending keys and Off disables scanning on admission.
+	// Only secrets that the policy doesn't ignore are considered. Requires the operator's admission webhooks to be enabled.
+	// +kubebuilder:validation:Enum=Enforce;Remediate;Warn;Off
+	// +kubebuilder:default=Off
+	AdmissionMode AdmissionMode `json:"admissionMode,omitempty"`
+
+	// FindingRetention defines what happens to the ExposedSecrets of a ConfigMap when the ConfigMap is deleted.
+	// Delete garbage collects them together with the ConfigMap. Retain keeps them for auditing:
+	// the ConfigMap is protected by a finalizer until its ExposedSecrets were resolved and released.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default=Delete
+	FindingRetention Retention `json:"findingRetention,omitempty"`
+
 	// GitleaksConfig allows customization of Gitleaks scanner behavior.
 	// If not specified, the default Gitleaks configuration will be used.
 	// +optional
@@ -48,10 +87,26 @@ type ScanPolicyStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
 	// LastProcessedTime is the last time this config was used during reconciliation.
+	// It is refreshed at most once a minute, unless the rest of the status changed.
 	LastProcessedTime metav1.Time `json:"lastProcessedTime,omitempty"`
 
 	// Message provides insight into the status of the config.
 	Message string `json:"message,omitempty"`
+
+	// EffectiveSpec is the spec applied to the namespace after merging all ScanPolicies in it.
+	// It is only set if the namespace contains multiple ScanPolicies.
+	// +optional
+	EffectiveSpec *ScanPolicySpec `json:"effectiveSpec,omitempty"`
+
+	// MergedPolicies lists the names of all ScanPolicies merged into the effective spec.
+	// +optional
+	MergedPolicies []string `json:"mergedPolicies,omitempty"`
+
+	// Conditions represent the latest available observations of the policy's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
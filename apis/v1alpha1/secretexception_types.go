@@ -0,0 +1,112 @@
+package v1alpha1
+
+import (
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SecretExceptionSpec defines which findings are excepted, why, by whom and until when.
+// All matchers that are set must match a finding; a list matches if any of its entries matches.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapNames) || has(self.configMapSelector) || has(self.keys) || has(self.ruleIDs) || has(self.fingerprints)",message="at least one of configMapNames, configMapSelector, keys, ruleIDs or fingerprints must be set"
+type SecretExceptionSpec struct {
+	// ConfigMapNames are glob patterns matching the names of the ConfigMaps, e.g. "app-*".
+	// +optional
+	ConfigMapNames []string `json:"configMapNames,omitempty"`
+
+	// ConfigMapSelector selects the ConfigMaps by their labels.
+	// +optional
+	ConfigMapSelector *metav1.LabelSelector `json:"configMapSelector,omitempty"`
+
+	// Keys are glob patterns matching the ConfigMap keys holding the secrets, e.g. "*.pem".
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// RuleIDs are the IDs of the scanner rules. If multiple rules detected a secret, all of them must be listed.
+	// +optional
+	RuleIDs []string `json:"ruleIDs,omitempty"`
+
+	// Fingerprints are the fingerprints of the secret values, see the ExposedSecret's status.fingerprint.
+	// +optional
+	Fingerprints []string `json:"fingerprints,omitempty"`
+
+	// Reason justifies the exception, e.g. "Test fixture, not a real credential".
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+
+	// Owner is the person or team responsible for the exception.
+	// +kubebuilder:validation:MinLength=1
+	Owner string `json:"owner"`
+
+	// ExpiresAt is the time the exception expires. Excepted findings are re-opened afterwards.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=sexc,scope=Namespaced
+// +kubebuilder:printcolumn:name="Owner",type=string,JSONPath=`.spec.owner`
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.spec.expiresAt`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.spec.reason`,priority=1
+
+// SecretException excepts findings in ConfigMaps of its namespace from being reported or remediated until it expires.
+// Excepted findings are reported as "Ignored" and re-opened once the exception expired or was deleted.
+type SecretException struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SecretExceptionSpec `json:"spec,omitempty"`
+}
+
+// Active reports whether the exception didn't expire at the given time.
+func (e *SecretException) Active(now time.Time) bool {
+	return now.Before(e.Spec.ExpiresAt.Time)
+}
+
+// Matches reports whether the exception applies to the finding in the ConfigMap.
+// The finding is described by its status, whose fingerprint is only compared if it is set.
+func (e *SecretException) Matches(cm *corev1.ConfigMap, status *ExposedSecretStatus, ruleIDs []string) (bool, error) {
+	s := &e.Spec
+	if len(s.ConfigMapNames) > 0 && !matchesAny(s.ConfigMapNames, cm.Name) {
+		return false, nil
+	}
+	if len(s.Keys) > 0 && !matchesAny(s.Keys, status.Key) {
+		return false, nil
+	}
+	if len(s.RuleIDs) > 0 && (len(ruleIDs) == 0 || !containsAll(s.RuleIDs, ruleIDs)) {
+		return false, nil
+	}
+	if len(s.Fingerprints) > 0 && (status.Fingerprint == "" || !slices.Contains(s.Fingerprints, status.Fingerprint)) {
+		return false, nil
+	}
+
+	if s.ConfigMapSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(s.ConfigMapSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(cm.Labels)), nil
+}
+
+// containsAll reports whether all values are part of the list.
+func containsAll(list, values []string) bool {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// +kubebuilder:object:root=true
+
+// SecretExceptionList contains a list of SecretException.
+type SecretExceptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretException `json:"items"`
+}
@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretLocation is a location of a fingerprinted secret value, reported by an ExposedSecret.
+type SecretLocation struct {
+	// Namespace of the ConfigMap and the ExposedSecret.
+	Namespace string `json:"namespace"`
+
+	// ConfigMap is the name of the ConfigMap holding the value.
+	ConfigMap string `json:"configMap"`
+
+	// Location is the location of the value inside the ConfigMap, e.g. "application.yaml:$.spring.datasource.password".
+	Location string `json:"location"`
+
+	// ExposedSecret is the name of the ExposedSecret reporting the value.
+	ExposedSecret string `json:"exposedSecret"`
+
+	// Phase is the phase of the ExposedSecret.
+	Phase Phase `json:"phase,omitempty"`
+}
+
+// SecretFingerprintStatus defines the observed state of SecretFingerprint
+type SecretFingerprintStatus struct {
+	// KeyID is the ID of the pepper the fingerprint was computed with.
+	// Fingerprints of different keys can't be compared.
+	// +optional
+	KeyID string `json:"keyID,omitempty"`
+
+	// Locations are all locations of the value, sorted by namespace, ConfigMap and location.
+	// +optional
+	Locations []SecretLocation `json:"locations,omitempty"`
+
+	// LocationCount is the number of locations.
+	LocationCount int `json:"locationCount"`
+
+	// ExposedCount is the number of locations still exposing the value in a ConfigMap,
+	// i.e. whose ExposedSecret is "Detected", "Ignored" or "Reverted".
+	// Once it drops to zero, all copies of the value were removed or remediated.
+	ExposedCount int `json:"exposedCount"`
+
+	// LastUpdateTime is the time the status was last updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=sfp,scope=Cluster
+// +kubebuilder:printcolumn:name="Locations",type=integer,JSONPath=`.status.locationCount`
+// +kubebuilder:printcolumn:name="Exposed",type=integer,JSONPath=`.status.exposedCount`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SecretFingerprint aggregates all ExposedSecrets reporting the same secret value across keys, ConfigMaps and namespaces.
+// It is named after the fingerprint of the value, see [NewFingerprint], and maintained by the operator.
+type SecretFingerprint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status SecretFingerprintStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretFingerprintList contains a list of SecretFingerprint.
+type SecretFingerprintList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretFingerprint `json:"items"`
+}
+
+// fingerprintLen is the number of hex characters of a fingerprint, so it is a valid label value and resource name.
+const fingerprintLen = 40
+
+// NewFingerprint returns a stable fingerprint of the secret value, keyed by the pepper.
+// Equal values have equal fingerprints, regardless of where they were found and which hash algorithm reported them.
+// It returns an empty fingerprint without a pepper, since unkeyed fingerprints of low-entropy secrets could be brute-forced.
+func NewFingerprint(secret string, pepper *Pepper) string {
+	if pepper == nil {
+		return ""
+	}
+	h := hmac.New(sha256.New, pepper.Key)
+	// The domain separates fingerprints from the values reported by AlgorithmHMACSHA256.
+	h.Write([]byte("fingerprint\x00"))
+	h.Write([]byte(secret))
+	return hex.EncodeToString(h.Sum(nil))[:fingerprintLen]
+}
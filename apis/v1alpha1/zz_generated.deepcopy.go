@@ -6,9 +6,96 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterScanPolicy) DeepCopyInto(out *ClusterScanPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterScanPolicy.
+func (in *ClusterScanPolicy) DeepCopy() *ClusterScanPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterScanPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterScanPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterScanPolicyList) DeepCopyInto(out *ClusterScanPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterScanPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterScanPolicyList.
+func (in *ClusterScanPolicyList) DeepCopy() *ClusterScanPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterScanPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterScanPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterScanPolicySpec) DeepCopyInto(out *ClusterScanPolicySpec) {
+	*out = *in
+	in.ScanPolicySpec.DeepCopyInto(&out.ScanPolicySpec)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterScanPolicySpec.
+func (in *ClusterScanPolicySpec) DeepCopy() *ClusterScanPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterScanPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
 	*out = *in
@@ -59,11 +146,21 @@ func (in *ExposedSecretBuilder) DeepCopyInto(out *ExposedSecretBuilder) {
 		*out = new(ExposedSecret)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.configMap != nil {
+		in, out := &in.configMap, &out.configMap
+		*out = new(corev1.ConfigMap)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.policy != nil {
 		in, out := &in.policy, &out.policy
 		*out = new(ScanPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.pepper != nil {
+		in, out := &in.pepper, &out.pepper
+		*out = new(Pepper)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposedSecretBuilder.
@@ -132,7 +229,23 @@ func (in *ExposedSecretStatus) DeepCopyInto(out *ExposedSecretStatus) {
 		*out = new(SecretReference)
 		**out = **in
 	}
+	if in.RewiredWorkloads != nil {
+		in, out := &in.RewiredWorkloads, &out.RewiredWorkloads
+		*out = make([]WorkloadReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolvedTime != nil {
+		in, out := &in.ResolvedTime, &out.ResolvedTime
+		*out = (*in).DeepCopy()
+	}
 	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposedSecretStatus.
@@ -145,6 +258,26 @@ func (in *ExposedSecretStatus) DeepCopy() *ExposedSecretStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pepper) DeepCopyInto(out *Pepper) {
+	*out = *in
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pepper.
+func (in *Pepper) DeepCopy() *Pepper {
+	if in == nil {
+		return nil
+	}
+	out := new(Pepper)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScanPolicy) DeepCopyInto(out *ScanPolicy) {
 	*out = *in
@@ -212,6 +345,18 @@ func (in *ScanPolicySpec) DeepCopyInto(out *ScanPolicySpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExcludedConfigMaps != nil {
+		in, out := &in.ExcludedConfigMaps, &out.ExcludedConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedConfigMapSelectors != nil {
+		in, out := &in.ExcludedConfigMapSelectors, &out.ExcludedConfigMapSelectors
+		*out = make([]v1.LabelSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.GitleaksConfig != nil {
 		in, out := &in.GitleaksConfig, &out.GitleaksConfig
 		*out = new(GitleaksConfig)
@@ -233,6 +378,23 @@ func (in *ScanPolicySpec) DeepCopy() *ScanPolicySpec {
 func (in *ScanPolicyStatus) DeepCopyInto(out *ScanPolicyStatus) {
 	*out = *in
 	in.LastProcessedTime.DeepCopyInto(&out.LastProcessedTime)
+	if in.EffectiveSpec != nil {
+		in, out := &in.EffectiveSpec, &out.EffectiveSpec
+		*out = new(ScanPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MergedPolicies != nil {
+		in, out := &in.MergedPolicies, &out.MergedPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanPolicyStatus.
@@ -245,6 +407,199 @@ func (in *ScanPolicyStatus) DeepCopy() *ScanPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretException) DeepCopyInto(out *SecretException) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretException.
+func (in *SecretException) DeepCopy() *SecretException {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretException)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretException) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretExceptionList) DeepCopyInto(out *SecretExceptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretException, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretExceptionList.
+func (in *SecretExceptionList) DeepCopy() *SecretExceptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretExceptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretExceptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretExceptionSpec) DeepCopyInto(out *SecretExceptionSpec) {
+	*out = *in
+	if in.ConfigMapNames != nil {
+		in, out := &in.ConfigMapNames, &out.ConfigMapNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapSelector != nil {
+		in, out := &in.ConfigMapSelector, &out.ConfigMapSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuleIDs != nil {
+		in, out := &in.RuleIDs, &out.RuleIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fingerprints != nil {
+		in, out := &in.Fingerprints, &out.Fingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretExceptionSpec.
+func (in *SecretExceptionSpec) DeepCopy() *SecretExceptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretExceptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretFingerprint) DeepCopyInto(out *SecretFingerprint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretFingerprint.
+func (in *SecretFingerprint) DeepCopy() *SecretFingerprint {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretFingerprint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretFingerprint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretFingerprintList) DeepCopyInto(out *SecretFingerprintList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretFingerprint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretFingerprintList.
+func (in *SecretFingerprintList) DeepCopy() *SecretFingerprintList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretFingerprintList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretFingerprintList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretFingerprintStatus) DeepCopyInto(out *SecretFingerprintStatus) {
+	*out = *in
+	if in.Locations != nil {
+		in, out := &in.Locations, &out.Locations
+		*out = make([]SecretLocation, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretFingerprintStatus.
+func (in *SecretFingerprintStatus) DeepCopy() *SecretFingerprintStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretFingerprintStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretLocation) DeepCopyInto(out *SecretLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretLocation.
+func (in *SecretLocation) DeepCopy() *SecretLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretReference) DeepCopyInto(out *SecretReference) {
 	*out = *in
@@ -259,3 +614,18 @@ func (in *SecretReference) DeepCopy() *SecretReference {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReference) DeepCopyInto(out *WorkloadReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReference.
+func (in *WorkloadReference) DeepCopy() *WorkloadReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -8,8 +8,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 )
 
-// maxLen is the maximum length of a DNS-1123 subdomain.
-const maxLen = 253
+// MaxNameLength is the maximum length of a DNS-1123 subdomain and thus of most resource names.
+const MaxNameLength = 253
 
 // invalidChar matches anything not a lower-case letter or digit
 var invalidChar = regexp.MustCompile(`[^a-z0-9]`)
@@ -34,8 +34,8 @@ func MakeDNS1123Subdomain(s string) string {
 		return "unknown"
 	}
 
-	if len(s) > maxLen {
-		s = s[:maxLen]
+	if len(s) > MaxNameLength {
+		s = s[:MaxNameLength]
 		s = strings.TrimRight(s, "-")
 	}
 	return s
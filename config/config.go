@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io/fs"
 	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/lvlcn-t/go-kit/config"
 	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
 	"github.com/lvlcn-t/secret-detection-operator/scanners"
+	"github.com/lvlcn-t/secret-detection-operator/scanners/factory"
 	"github.com/lvlcn-t/secret-detection-operator/scanners/gitleaks"
 	"github.com/spf13/afero"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -35,8 +38,58 @@ type Config struct {
 	// ScanPolicy is the default scan policy to use for scanning secrets.
 	// It is used when no scan policy is present in the namespace of the ConfigMap that is being reconciled.
 	ScanPolicy *v1alpha1.ScanPolicy
+
+	// Webhook configures the admission webhooks served by the operator.
+	Webhook Webhook
+
+	// ResolvedTTL is the time after which resolved ExposedSecrets are deleted.
+	// Resolved ExposedSecrets are kept forever if it is zero.
+	ResolvedTTL time.Duration
+
+	// ResyncInterval is the interval after which every ConfigMap is scanned again, even if it didn't change,
+	// e.g. to apply new upstream scanner rules to old data. ConfigMaps are only rescanned on changes if it is zero.
+	ResyncInterval time.Duration
+
+	// ScannerCacheSize is the number of scanners built from custom policy configurations that are kept in memory.
+	// Scanners are rebuilt on every reconciliation if it is negative.
+	ScannerCacheSize int
+
+	// Manager configures the controller manager running the operator.
+	// It can be overridden by command line flags, see [BindFlags].
+	Manager Manager
+
+	// Watch restricts the namespaces and ConfigMaps the operator watches.
+	Watch Watch
+
+	// Pepper references the Secret holding the key of the HMAC hashing algorithms.
+	// It must be loaded before use, see [Pepper.Load].
+	Pepper Pepper
+
+	// AllowPlaintext allows policies to report the base64 encoded plaintext of detected secrets
+	// with the hash algorithm none. If it is false, such values are masked instead.
+	AllowPlaintext bool
 }
 
+// Webhook configures the admission webhooks served by the operator.
+type Webhook struct {
+	// Enabled enables the admission webhooks.
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Port is the port the webhook server listens on.
+	Port int `json:"port" yaml:"port" mapstructure:"port"`
+	// CertDir is the directory containing the webhook server's tls.crt and tls.key.
+	CertDir string `json:"certDir" yaml:"certDir" mapstructure:"certDir"`
+	// SinglePolicyPerNamespace rejects a ScanPolicy if another ScanPolicy exists in its namespace.
+	// If disabled, multiple ScanPolicies are allowed as long as they can be merged without conflicts.
+	SinglePolicyPerNamespace bool `json:"singlePolicyPerNamespace" yaml:"singlePolicyPerNamespace" mapstructure:"singlePolicyPerNamespace"`
+}
+
+const (
+	// defaultWebhookPort is the default port of the webhook server.
+	defaultWebhookPort = 9443
+	// defaultWebhookCertDir is the default directory of the webhook server's certificates.
+	defaultWebhookCertDir = "/tmp/k8s-webhook-server/serving-certs"
+)
+
 // Validate validates the [Config] against the Kubernetes API server.
 func (cfg *Config) Validate(ctx context.Context, c client.Client) error {
 	var errs []error
@@ -50,7 +103,8 @@ func (cfg *Config) Validate(ctx context.Context, c client.Client) error {
 
 // Load loads the [Config] from the provided path.
 // If the path is empty, it will use the default fallback path (~/.config/[AppName]/config.yaml).
-// It will also load the configuration from the environment variables with the prefix [AppName].
+// It will also load the configuration from the environment variables with the prefix [AppName],
+// e.g. SECRET_DETECTION_OPERATOR_MANAGER_LEADERELECTION_ENABLED=true.
 func Load(path string) (*Config, error) {
 	config.SetName(AppName)
 	cfg, err := config.Load[rawConfig](path)
@@ -71,11 +125,21 @@ func LoadFS(path string, fsys fs.FS) (*Config, error) {
 // rawConfig is the raw configuration struct which is compliant with a Kubernetes ConfigMap.
 // It is used to unmarshal the configuration from the file or environment variables.
 type rawConfig struct {
-	ScanPolicy string `json:"defaultScanPolicy" yaml:"defaultScanPolicy" mapstructure:"defaultScanPolicy"`
+	ScanPolicy  string  `json:"defaultScanPolicy" yaml:"defaultScanPolicy" mapstructure:"defaultScanPolicy"`
+	Webhook     Webhook `json:"webhook" yaml:"webhook" mapstructure:"webhook"`
+	ResolvedTTL string  `json:"resolvedTTL" yaml:"resolvedTTL" mapstructure:"resolvedTTL"`
+	// ResyncInterval is parsed as a [time.Duration], e.g. "24h".
+	ResyncInterval string `json:"resyncInterval" yaml:"resyncInterval" mapstructure:"resyncInterval"`
+	// ScannerCacheSize defaults to [factory.DefaultCacheSize] if it is zero.
+	ScannerCacheSize int     `json:"scannerCacheSize" yaml:"scannerCacheSize" mapstructure:"scannerCacheSize"`
+	Manager          Manager `json:"manager" yaml:"manager" mapstructure:"manager"`
+	Watch            Watch   `json:"watch" yaml:"watch" mapstructure:"watch"`
+	Pepper           Pepper  `json:"pepper" yaml:"pepper" mapstructure:"pepper"`
+	AllowPlaintext   bool    `json:"allowPlaintext" yaml:"allowPlaintext" mapstructure:"allowPlaintext"`
 }
 
 func (rc rawConfig) IsEmpty() bool {
-	return cmp.Equal(rc, rawConfig{})
+	return cmp.Equal(rc, rawConfig{}, cmpopts.IgnoreUnexported(Watch{}, Pepper{}))
 }
 
 func (rc *rawConfig) toConfig() (c *Config, err error) {
@@ -85,6 +149,59 @@ func (rc *rawConfig) toConfig() (c *Config, err error) {
 		return nil, fmt.Errorf("failed to decode scan policy: %w", err)
 	}
 
+	cfg.Webhook = rc.Webhook
+	if cfg.Webhook.Port == 0 {
+		cfg.Webhook.Port = defaultWebhookPort
+	}
+	if cfg.Webhook.CertDir == "" {
+		cfg.Webhook.CertDir = defaultWebhookCertDir
+	}
+
+	if rc.ResolvedTTL != "" {
+		cfg.ResolvedTTL, err = time.ParseDuration(rc.ResolvedTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse resolved TTL: %w", err)
+		}
+		if cfg.ResolvedTTL < 0 {
+			return nil, fmt.Errorf("resolved TTL must not be negative, got %s", cfg.ResolvedTTL)
+		}
+	}
+
+	if rc.ResyncInterval != "" {
+		cfg.ResyncInterval, err = time.ParseDuration(rc.ResyncInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse resync interval: %w", err)
+		}
+		if cfg.ResyncInterval < 0 {
+			return nil, fmt.Errorf("resync interval must not be negative, got %s", cfg.ResyncInterval)
+		}
+	}
+
+	cfg.ScannerCacheSize = rc.ScannerCacheSize
+	if cfg.ScannerCacheSize == 0 {
+		cfg.ScannerCacheSize = factory.DefaultCacheSize
+	}
+
+	cfg.Manager = rc.Manager
+	cfg.Manager.setDefaults()
+	if err = cfg.Manager.validate(); err != nil {
+		return nil, fmt.Errorf("invalid manager configuration: %w", err)
+	}
+
+	cfg.Watch = rc.Watch
+	if err = cfg.Watch.parse(); err != nil {
+		return nil, fmt.Errorf("invalid watch configuration: %w", err)
+	}
+
+	cfg.Pepper = rc.Pepper
+	cfg.AllowPlaintext = rc.AllowPlaintext
+	if err = cfg.Pepper.validate(); err != nil {
+		return nil, fmt.Errorf("invalid pepper configuration: %w", err)
+	}
+	if alg := cfg.ScanPolicy.Spec.HashAlgorithm; alg.Keyed() && !cfg.Pepper.Enabled() {
+		return nil, fmt.Errorf("hash algorithm %s of the scan policy requires a pepper", alg)
+	}
+
 	return &cfg, nil
 }
 
@@ -98,10 +215,11 @@ var validationMeta = metav1.ObjectMeta{
 var defaultScanPolicy = &v1alpha1.ScanPolicy{
 	ObjectMeta: validationMeta,
 	Spec: v1alpha1.ScanPolicySpec{
-		Action:        v1alpha1.ActionReportOnly,
-		MinSeverity:   scanners.SeverityMedium,
-		Scanner:       gitleaks.Name,
-		HashAlgorithm: v1alpha1.AlgorithmNone,
+		Action:           v1alpha1.ActionReportOnly,
+		MinSeverity:      scanners.SeverityMedium,
+		Scanner:          gitleaks.Name,
+		HashAlgorithm:    v1alpha1.AlgorithmMasked,
+		FindingRetention: v1alpha1.RetentionDelete,
 	},
 }
 
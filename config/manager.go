@@ -0,0 +1,201 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// Manager configures the controller manager running the operator.
+type Manager struct {
+	// MetricsAddr is the address the metrics endpoint binds to.
+	MetricsAddr string `json:"metricsAddr" yaml:"metricsAddr" mapstructure:"metricsAddr"`
+	// HealthAddr is the address the health probe endpoints bind to.
+	HealthAddr string `json:"healthAddr" yaml:"healthAddr" mapstructure:"healthAddr"`
+	// LeaderElection configures the leader election, needed to run multiple replicas.
+	LeaderElection LeaderElection `json:"leaderElection" yaml:"leaderElection" mapstructure:"leaderElection"`
+	// MaxConcurrentReconciles is the number of ConfigMaps reconciled concurrently.
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles" yaml:"maxConcurrentReconciles" mapstructure:"maxConcurrentReconciles"`
+	// RateLimiter limits how often ConfigMaps are requeued.
+	RateLimiter RateLimiter `json:"rateLimiter" yaml:"rateLimiter" mapstructure:"rateLimiter"`
+	// SyncPeriod is the interval after which the informers resync all watched objects.
+	// The controller-runtime default is used if it is zero.
+	SyncPeriod time.Duration `json:"syncPeriod" yaml:"syncPeriod" mapstructure:"syncPeriod"`
+}
+
+// LeaderElection configures the leader election of the controller manager.
+type LeaderElection struct {
+	// Enabled enables the leader election.
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Namespace is the namespace of the lease. Defaults to the namespace the operator runs in.
+	Namespace string `json:"namespace" yaml:"namespace" mapstructure:"namespace"`
+	// LeaseDuration is the duration non-leaders wait before acquiring a lease that wasn't renewed.
+	LeaseDuration time.Duration `json:"leaseDuration" yaml:"leaseDuration" mapstructure:"leaseDuration"`
+	// RenewDeadline is the duration the leader retries to renew the lease before giving it up.
+	RenewDeadline time.Duration `json:"renewDeadline" yaml:"renewDeadline" mapstructure:"renewDeadline"`
+	// RetryPeriod is the duration between attempts to acquire or renew the lease.
+	RetryPeriod time.Duration `json:"retryPeriod" yaml:"retryPeriod" mapstructure:"retryPeriod"`
+}
+
+// RateLimiter configures the rate limiting of ConfigMap reconciliations.
+// A ConfigMap is requeued after the longer delay of the per-item exponential backoff and the overall token bucket.
+type RateLimiter struct {
+	// BaseDelay is the delay of the first retry of a failed reconciliation. It doubles on every failure.
+	BaseDelay time.Duration `json:"baseDelay" yaml:"baseDelay" mapstructure:"baseDelay"`
+	// MaxDelay is the maximum delay of a retry.
+	MaxDelay time.Duration `json:"maxDelay" yaml:"maxDelay" mapstructure:"maxDelay"`
+	// QPS is the overall number of reconciliations per second.
+	QPS float64 `json:"qps" yaml:"qps" mapstructure:"qps"`
+	// Burst is the overall number of reconciliations allowed to exceed the QPS.
+	Burst int `json:"burst" yaml:"burst" mapstructure:"burst"`
+}
+
+const (
+	// defaultMetricsAddr is the default address of the metrics endpoint.
+	defaultMetricsAddr = ":9090"
+	// defaultHealthAddr is the default address of the health probe endpoints.
+	defaultHealthAddr = ":8080"
+	// defaultMaxConcurrentReconciles is the default number of concurrent reconciliations.
+	defaultMaxConcurrentReconciles = 1
+)
+
+// The defaults of the leader election and the rate limiter are the ones of controller-runtime and client-go.
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+	defaultBaseDelay     = 5 * time.Millisecond
+	defaultMaxDelay      = 1000 * time.Second
+	defaultQPS           = 10
+	defaultBurst         = 100
+)
+
+// setDefaults sets the defaults of all unset fields.
+func (m *Manager) setDefaults() {
+	if m.MetricsAddr == "" {
+		m.MetricsAddr = defaultMetricsAddr
+	}
+	if m.HealthAddr == "" {
+		m.HealthAddr = defaultHealthAddr
+	}
+	if m.MaxConcurrentReconciles == 0 {
+		m.MaxConcurrentReconciles = defaultMaxConcurrentReconciles
+	}
+
+	le := &m.LeaderElection
+	if le.LeaseDuration == 0 {
+		le.LeaseDuration = defaultLeaseDuration
+	}
+	if le.RenewDeadline == 0 {
+		le.RenewDeadline = defaultRenewDeadline
+	}
+	if le.RetryPeriod == 0 {
+		le.RetryPeriod = defaultRetryPeriod
+	}
+
+	rl := &m.RateLimiter
+	if rl.BaseDelay == 0 {
+		rl.BaseDelay = defaultBaseDelay
+	}
+	if rl.MaxDelay == 0 {
+		rl.MaxDelay = defaultMaxDelay
+	}
+	if rl.QPS == 0 {
+		rl.QPS = defaultQPS
+	}
+	if rl.Burst == 0 {
+		rl.Burst = defaultBurst
+	}
+}
+
+// validate validates the [Manager] configuration after the defaults were set.
+func (m *Manager) validate() error {
+	var errs []error
+	if m.MaxConcurrentReconciles < 0 {
+		errs = append(errs, fmt.Errorf("max concurrent reconciles must not be negative, got %d", m.MaxConcurrentReconciles))
+	}
+	if m.SyncPeriod < 0 {
+		errs = append(errs, fmt.Errorf("sync period must not be negative, got %s", m.SyncPeriod))
+	}
+
+	le := m.LeaderElection
+	if le.LeaseDuration <= le.RenewDeadline {
+		errs = append(errs, fmt.Errorf("lease duration (%s) must be greater than the renew deadline (%s)", le.LeaseDuration, le.RenewDeadline))
+	}
+	if le.RetryPeriod <= 0 || le.RenewDeadline <= le.RetryPeriod {
+		errs = append(errs, fmt.Errorf("renew deadline (%s) must be greater than the retry period (%s)", le.RenewDeadline, le.RetryPeriod))
+	}
+
+	rl := m.RateLimiter
+	if rl.BaseDelay < 0 || rl.MaxDelay < rl.BaseDelay {
+		errs = append(errs, fmt.Errorf("rate limiter delays must satisfy 0 <= base delay (%s) <= max delay (%s)", rl.BaseDelay, rl.MaxDelay))
+	}
+	if rl.QPS < 0 || rl.Burst < 0 {
+		errs = append(errs, fmt.Errorf("rate limiter qps (%g) and burst (%d) must not be negative", rl.QPS, rl.Burst))
+	}
+	return errors.Join(errs...)
+}
+
+// Flags are the command line flags overriding the [Manager] configuration.
+// Only flags that are set explicitly override the configuration.
+type Flags struct {
+	fs     *flag.FlagSet
+	values Manager
+	// apply copies the value of the flag with the given name to the configuration.
+	apply map[string]func(m *Manager)
+}
+
+// BindFlags registers the flags of the [Manager] configuration on the flag set.
+func BindFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs, apply: map[string]func(m *Manager){}}
+	v := &f.values
+	bind := func(name string, apply func(m *Manager)) { f.apply[name] = apply }
+
+	fs.StringVar(&v.MetricsAddr, "metrics-bind-address", defaultMetricsAddr, "The address the metrics endpoint binds to")
+	bind("metrics-bind-address", func(m *Manager) { m.MetricsAddr = v.MetricsAddr })
+	fs.StringVar(&v.HealthAddr, "health-probe-bind-address", defaultHealthAddr, "The address the health probe endpoints bind to")
+	bind("health-probe-bind-address", func(m *Manager) { m.HealthAddr = v.HealthAddr })
+	fs.IntVar(&v.MaxConcurrentReconciles, "max-concurrent-reconciles", defaultMaxConcurrentReconciles, "The number of ConfigMaps reconciled concurrently")
+	bind("max-concurrent-reconciles", func(m *Manager) { m.MaxConcurrentReconciles = v.MaxConcurrentReconciles })
+	fs.DurationVar(&v.SyncPeriod, "sync-period", 0, "The interval after which the informers resync all watched objects")
+	bind("sync-period", func(m *Manager) { m.SyncPeriod = v.SyncPeriod })
+
+	le := &v.LeaderElection
+	fs.BoolVar(&le.Enabled, "leader-elect", false, "Enable leader election to run multiple replicas")
+	bind("leader-elect", func(m *Manager) { m.LeaderElection.Enabled = le.Enabled })
+	fs.StringVar(&le.Namespace, "leader-election-namespace", "", "The namespace of the leader election lease")
+	bind("leader-election-namespace", func(m *Manager) { m.LeaderElection.Namespace = le.Namespace })
+	fs.DurationVar(&le.LeaseDuration, "leader-election-lease-duration", defaultLeaseDuration, "The duration non-leaders wait before acquiring a lease")
+	bind("leader-election-lease-duration", func(m *Manager) { m.LeaderElection.LeaseDuration = le.LeaseDuration })
+	fs.DurationVar(&le.RenewDeadline, "leader-election-renew-deadline", defaultRenewDeadline, "The duration the leader retries to renew the lease")
+	bind("leader-election-renew-deadline", func(m *Manager) { m.LeaderElection.RenewDeadline = le.RenewDeadline })
+	fs.DurationVar(&le.RetryPeriod, "leader-election-retry-period", defaultRetryPeriod, "The duration between attempts to acquire or renew the lease")
+	bind("leader-election-retry-period", func(m *Manager) { m.LeaderElection.RetryPeriod = le.RetryPeriod })
+
+	rl := &v.RateLimiter
+	fs.DurationVar(&rl.BaseDelay, "rate-limiter-base-delay", defaultBaseDelay, "The delay of the first retry of a failed reconciliation")
+	bind("rate-limiter-base-delay", func(m *Manager) { m.RateLimiter.BaseDelay = rl.BaseDelay })
+	fs.DurationVar(&rl.MaxDelay, "rate-limiter-max-delay", defaultMaxDelay, "The maximum delay of a retry")
+	bind("rate-limiter-max-delay", func(m *Manager) { m.RateLimiter.MaxDelay = rl.MaxDelay })
+	fs.Float64Var(&rl.QPS, "rate-limiter-qps", defaultQPS, "The overall number of reconciliations per second")
+	bind("rate-limiter-qps", func(m *Manager) { m.RateLimiter.QPS = rl.QPS })
+	fs.IntVar(&rl.Burst, "rate-limiter-burst", defaultBurst, "The overall number of reconciliations allowed to exceed the QPS")
+	bind("rate-limiter-burst", func(m *Manager) { m.RateLimiter.Burst = rl.Burst })
+
+	return f
+}
+
+// Apply overrides the configuration with the flags set explicitly and validates the result.
+// It must be called after the flag set was parsed.
+func (f *Flags) Apply(cfg *Config) error {
+	f.fs.Visit(func(fl *flag.Flag) {
+		if apply, ok := f.apply[fl.Name]; ok {
+			apply(&cfg.Manager)
+		}
+	})
+	if err := cfg.Manager.validate(); err != nil {
+		return fmt.Errorf("invalid manager configuration: %w", err)
+	}
+	return nil
+}
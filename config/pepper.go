@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// minPepperLength is the minimum length of a pepper in bytes.
+const minPepperLength = 32
+
+// Pepper references the Secret holding the key of the HMAC hashing algorithms, see [v1alpha1.Pepper].
+// The Secret may hold multiple keys. To rotate the pepper, add a new key to the Secret and change the KeyID.
+type Pepper struct {
+	// Namespace is the namespace of the Secret.
+	Namespace string `json:"namespace" yaml:"namespace" mapstructure:"namespace"`
+	// SecretName is the name of the Secret.
+	SecretName string `json:"secretName" yaml:"secretName" mapstructure:"secretName"`
+	// KeyID is the key of the pepper in the Secret's data. It is part of every HMAC hash.
+	KeyID string `json:"keyID" yaml:"keyID" mapstructure:"keyID"`
+
+	// key is the pepper loaded from the Secret, see [Pepper.Load].
+	key *v1alpha1.Pepper
+}
+
+// Enabled reports whether a pepper is configured.
+func (p *Pepper) Enabled() bool {
+	return p.SecretName != ""
+}
+
+// validate validates the reference to the Secret.
+func (p *Pepper) validate() error {
+	if !p.Enabled() {
+		return nil
+	}
+	var errs []error
+	if p.Namespace == "" {
+		errs = append(errs, errors.New("pepper namespace must not be empty"))
+	}
+	if p.KeyID == "" {
+		errs = append(errs, errors.New("pepper key ID must not be empty"))
+	}
+	return errors.Join(errs...)
+}
+
+// Load reads the pepper from the referenced Secret. It does nothing if no pepper is configured.
+// The reader must be able to read the Secret without a cache, e.g. the manager's API reader.
+func (p *Pepper) Load(ctx context.Context, r client.Reader) error {
+	if !p.Enabled() {
+		return nil
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: p.Namespace, Name: p.SecretName}, &secret); err != nil {
+		return fmt.Errorf("failed to get pepper Secret: %w", err)
+	}
+	key, ok := secret.Data[p.KeyID]
+	if !ok {
+		return fmt.Errorf("pepper Secret %s/%s has no key %q", p.Namespace, p.SecretName, p.KeyID)
+	}
+	if len(key) < minPepperLength {
+		return fmt.Errorf("pepper %q must have at least %d bytes, got %d", p.KeyID, minPepperLength, len(key))
+	}
+
+	p.key = &v1alpha1.Pepper{ID: p.KeyID, Key: key}
+	return nil
+}
+
+// Get returns the loaded pepper or nil if no pepper is configured.
+func (p *Pepper) Get() *v1alpha1.Pepper {
+	return p.key
+}
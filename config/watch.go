@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Watch restricts the namespaces and ConfigMaps the operator watches.
+// ConfigMaps outside of its scope are neither cached nor scanned.
+type Watch struct {
+	// Namespaces are the namespaces to watch. All namespaces are watched if it is empty.
+	Namespaces []string `json:"namespaces" yaml:"namespaces" mapstructure:"namespaces"`
+	// ExcludedNamespaces are the namespaces never watched, even if they are part of Namespaces.
+	ExcludedNamespaces []string `json:"excludedNamespaces" yaml:"excludedNamespaces" mapstructure:"excludedNamespaces"`
+	// NamespaceSelector is a label selector the namespaces of watched ConfigMaps must match, e.g. "team=a".
+	NamespaceSelector string `json:"namespaceSelector" yaml:"namespaceSelector" mapstructure:"namespaceSelector"`
+	// ConfigMapSelector is a label selector watched ConfigMaps must match, e.g. "app.kubernetes.io/managed-by!=Helm".
+	ConfigMapSelector string `json:"configMapSelector" yaml:"configMapSelector" mapstructure:"configMapSelector"`
+	// ConfigMapFieldSelector is a field selector watched ConfigMaps must match, e.g. "metadata.name!=kube-root-ca.crt".
+	// Only the fields metadata.name and metadata.namespace are supported.
+	ConfigMapFieldSelector string `json:"configMapFieldSelector" yaml:"configMapFieldSelector" mapstructure:"configMapFieldSelector"`
+	// Namespaced runs the operator with Roles in the watched namespaces instead of a ClusterRole.
+	// Cluster-scoped resources aren't watched then, so ClusterScanPolicies are ignored.
+	// It requires Namespaces and can't be combined with a NamespaceSelector.
+	Namespaced bool `json:"namespaced" yaml:"namespaced" mapstructure:"namespaced"`
+
+	// namespaceSelector, configMapSelector and configMapFields are the parsed selectors.
+	namespaceSelector labels.Selector
+	configMapSelector labels.Selector
+	configMapFields   fields.Selector
+}
+
+// parse parses and validates the selectors.
+func (w *Watch) parse() error {
+	var errs []error
+	var err error
+	if w.namespaceSelector, err = labels.Parse(w.NamespaceSelector); err != nil {
+		errs = append(errs, fmt.Errorf("invalid namespace selector: %w", err))
+	}
+	if w.configMapSelector, err = labels.Parse(w.ConfigMapSelector); err != nil {
+		errs = append(errs, fmt.Errorf("invalid ConfigMap selector: %w", err))
+	}
+	if w.configMapFields, err = fields.ParseSelector(w.ConfigMapFieldSelector); err != nil {
+		errs = append(errs, fmt.Errorf("invalid ConfigMap field selector: %w", err))
+	}
+	for _, r := range w.configMapFields.Requirements() {
+		if r.Field != "metadata.name" && r.Field != "metadata.namespace" {
+			errs = append(errs, fmt.Errorf("unsupported field %q in ConfigMap field selector", r.Field))
+		}
+	}
+
+	if len(w.Namespaces) > 0 && !slices.ContainsFunc(w.Namespaces, w.WatchesNamespace) {
+		errs = append(errs, errors.New("all namespaces to watch are excluded"))
+	}
+	if w.Namespaced {
+		if len(w.Namespaces) == 0 {
+			errs = append(errs, errors.New("namespaced mode requires namespaces to watch"))
+		}
+		if w.NamespaceSelector != "" {
+			errs = append(errs, errors.New("namespaced mode can't be combined with a namespace selector"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WatchesNamespace reports whether the namespace is part of the allow list and not excluded.
+// It doesn't consider the namespace selector, see [Watch.MatchesNamespace].
+func (w *Watch) WatchesNamespace(namespace string) bool {
+	if slices.Contains(w.ExcludedNamespaces, namespace) {
+		return false
+	}
+	return len(w.Namespaces) == 0 || slices.Contains(w.Namespaces, namespace)
+}
+
+// MatchesNamespace reports whether the namespace's labels match the namespace selector.
+func (w *Watch) MatchesNamespace(nsLabels map[string]string) bool {
+	return w.NamespaceSelector == "" || w.namespaceSelector.Matches(labels.Set(nsLabels))
+}
+
+// HasNamespaceSelector reports whether the namespaces of watched ConfigMaps are selected by their labels.
+func (w *Watch) HasNamespaceSelector() bool {
+	return w.NamespaceSelector != ""
+}
+
+// MatchesConfigMap reports whether the ConfigMap is in a watched namespace and matches the ConfigMap selectors.
+// It doesn't consider the namespace selector, since that requires the namespace, see [Watch.MatchesNamespace].
+func (w *Watch) MatchesConfigMap(cm client.Object) bool {
+	if !w.WatchesNamespace(cm.GetNamespace()) {
+		return false
+	}
+	if w.ConfigMapSelector != "" && !w.configMapSelector.Matches(labels.Set(cm.GetLabels())) {
+		return false
+	}
+	return w.ConfigMapFieldSelector == "" || w.configMapFields.Matches(fields.Set{
+		"metadata.name":      cm.GetName(),
+		"metadata.namespace": cm.GetNamespace(),
+	})
+}
+
+// CacheOptions returns the options of the manager's cache, so objects outside of the watched namespaces
+// and ConfigMaps not matching the selectors are never cached.
+func (cfg *Config) CacheOptions() cache.Options {
+	w := &cfg.Watch
+	opts := cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {Label: w.configMapSelector, Field: w.configMapFields},
+		},
+	}
+	if cfg.Manager.SyncPeriod > 0 {
+		opts.SyncPeriod = &cfg.Manager.SyncPeriod
+	}
+
+	if len(w.Namespaces) > 0 {
+		opts.DefaultNamespaces = map[string]cache.Config{}
+		for _, ns := range w.Namespaces {
+			if w.WatchesNamespace(ns) {
+				opts.DefaultNamespaces[ns] = cache.Config{}
+			}
+		}
+		return opts
+	}
+
+	if len(w.ExcludedNamespaces) > 0 {
+		excluded := make([]fields.Selector, 0, len(w.ExcludedNamespaces))
+		for _, ns := range w.ExcludedNamespaces {
+			excluded = append(excluded, fields.OneTermNotEqualSelector("metadata.namespace", ns))
+		}
+		opts.DefaultNamespaces = map[string]cache.Config{
+			cache.AllNamespaces: {FieldSelector: fields.AndSelectors(excluded...)},
+		}
+	}
+	return opts
+}
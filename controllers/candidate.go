@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"github.com/lvlcn-t/secret-detection-operator/scanners"
+	"github.com/lvlcn-t/secret-detection-operator/scanners/archive"
+	"github.com/lvlcn-t/secret-detection-operator/scanners/structured"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// candidate is a secret-like value found in a [corev1.ConfigMap].
+type candidate struct {
+	// key is the ConfigMap key holding the value.
+	key string
+	// binary is true if the key is part of the ConfigMap's binaryData.
+	binary bool
+	// innerPath is the path of the file inside a binary container holding the value.
+	innerPath string
+	// path is the JSONPath of the value inside a structured file.
+	path string
+	// value is the detected value.
+	value string
+	// findings are the findings of the scanner for the value.
+	findings []scanners.Finding
+}
+
+// severity returns the highest severity of the candidate's findings.
+func (c *candidate) severity() scanners.Severity {
+	return scanners.MaxSeverity(c.findings)
+}
+
+// name returns the name of the [v1alpha1.ExposedSecret] reporting the candidate.
+func (c *candidate) name(cm *corev1.ConfigMap) string {
+	return v1alpha1.NewExposedSecretName(cm, c.key, c.innerPath, c.path)
+}
+
+// location returns the location of the candidate, see [v1alpha1.ExposedSecretStatus.Location].
+func (c *candidate) location() string {
+	status := v1alpha1.ExposedSecretStatus{Key: c.key, InnerPath: c.innerPath, Path: c.path}
+	return status.Location()
+}
+
+// ruleIDs returns the sorted IDs of the rules that detected the candidate.
+func (c *candidate) ruleIDs() []string {
+	ids := make([]string, 0, len(c.findings))
+	for i := range c.findings {
+		ids = append(ids, c.findings[i].RuleID)
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// present reports whether the key of the candidate is still part of the ConfigMap.
+func (c *candidate) present(cm *corev1.ConfigMap) bool {
+	if c.binary {
+		_, ok := cm.BinaryData[c.key]
+		return ok
+	}
+	_, ok := cm.Data[c.key]
+	return ok
+}
+
+// findCandidates returns all values in the ConfigMap's data and binaryData
+// that match the scanner's secret pattern.
+func (rc *recCtx) findCandidates() ([]candidate, error) {
+	var candidates []candidate
+	for key, value := range rc.configMap.Data {
+		found, err := rc.scanValue(candidate{key: key, value: value}, key)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, found...)
+	}
+
+	for key, value := range rc.configMap.BinaryData {
+		found, err := rc.findBinaryCandidates(key, value)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, found...)
+	}
+	return candidates, nil
+}
+
+// findBinaryCandidates decodes a binaryData value and scans every file inside of it.
+// Keystores are encrypted and can't be scanned, so they are always reported: keystores holding a
+// private key with severity High and truststores only holding certificates with severity Low,
+// so they are ignored unless the policy's minimum severity includes them.
+func (rc *recCtx) findBinaryCandidates(key string, value []byte) ([]candidate, error) {
+	entries, err := archive.Extract(value)
+	if err != nil {
+		// We still scan the raw payload, so a broken or oversized
+		// archive can't be used to hide a plaintext secret.
+		rc.log.WarnContext(rc.ctx, "Failed to extract binary data, scanning raw payload", "key", key, "error", err)
+		entries = []archive.Entry{{Format: archive.FormatRaw, Data: value}}
+	}
+
+	var candidates []candidate
+	for _, entry := range entries {
+		c := candidate{key: key, binary: true, innerPath: entry.Path, value: string(entry.Data)}
+		if entry.Format.IsKeystore() {
+			hasKey := archive.HasPrivateKey(entry.Format, entry.Data)
+			rc.log.DebugContext(rc.ctx, "Found keystore in binary data", "key", key, "innerPath", entry.Path, "format", entry.Format, "privateKey", hasKey)
+			finding := scanners.Finding{
+				RuleID:      entry.Format.String() + "-keystore",
+				Description: fmt.Sprintf("Encrypted %s keystore with a private key", strings.ToUpper(entry.Format.String())),
+				StartLine:   1,
+				EndLine:     1,
+				Severity:    scanners.SeverityHigh,
+			}
+			if !hasKey {
+				finding.RuleID = entry.Format.String() + "-truststore"
+				finding.Description = fmt.Sprintf("Encrypted %s truststore without a private key", strings.ToUpper(entry.Format.String()))
+				finding.Severity = scanners.SeverityLow
+			}
+			c.findings = []scanners.Finding{finding}
+			candidates = append(candidates, c)
+			continue
+		}
+
+		found, err := rc.scanValue(c, cmp.Or(entry.Path, key))
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, found...)
+	}
+	return candidates, nil
+}
+
+// scanValue scans the value of the candidate and returns it if it holds a secret.
+// Structured values are expanded into a candidate for every nested value that is a secret on its own.
+// The file name is used to guess the format of the value.
+func (rc *recCtx) scanValue(c candidate, file string) ([]candidate, error) {
+	findings, err := rc.scanner.Scan(rc.ctx, c.value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan key %q: %w", c.key, err)
+	}
+	if len(findings) == 0 {
+		return nil, nil
+	}
+	c.findings = findings
+
+	nested, err := rc.expand(c, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(nested) == 0 {
+		return []candidate{c}, nil
+	}
+	return nested, nil
+}
+
+// expand parses the value of a secret-like candidate as a structured file and returns
+// a candidate for every nested value that is a secret on its own.
+// It returns no candidates if the value isn't structured or no nested value is a secret.
+func (rc *recCtx) expand(c candidate, file string) ([]candidate, error) {
+	format := structured.Guess(file, c.value)
+	if format == structured.FormatUnknown {
+		return nil, nil
+	}
+
+	leaves, err := structured.Parse(format, c.value)
+	if err != nil {
+		rc.log.DebugContext(rc.ctx, "Failed to parse structured value, scanning it as a whole", "key", c.key, "format", format, "error", err)
+		return nil, nil
+	}
+
+	var nested []candidate
+	// used holds the offsets of the values already located in the file, so repeated values are located one after another.
+	used := map[int]bool{}
+	for _, leaf := range leaves {
+		findings, err := rc.scanner.Scan(rc.ctx, leaf.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan key %q at %q: %w", c.key, leaf.Path, err)
+		}
+		if len(findings) == 0 {
+			continue
+		}
+
+		n := c
+		n.path = leaf.Path
+		n.value = leaf.Value
+		n.findings = relocate(findings, leaf, c.value, used)
+		nested = append(nested, n)
+	}
+	return nested, nil
+}
+
+// relocate translates the positions of findings in a scanned leaf ("name: value")
+// to positions in the structured file the leaf was parsed from.
+// The offsets of the values already located are skipped, see [locateLeaf].
+func relocate(findings []scanners.Finding, leaf structured.Leaf, file string, used map[int]bool) []scanners.Finding {
+	offset := locateLeaf(leaf, file, used)
+	if offset < 0 {
+		// The value was transformed while parsing (e.g. escaped or joined lines), so
+		// we can't tell where it's located exactly. Keep the positions in the leaf.
+		return findings
+	}
+	used[offset] = true
+	prefix := len(leaf.Name) + len(": ")
+	line := strings.Count(file[:offset], "\n")
+
+	for i := range findings {
+		f := &findings[i]
+		f.Start = offset + max(f.Start-prefix, 0)
+		f.End = max(f.Start, offset+f.End-prefix)
+		f.StartLine += line
+		f.EndLine += line
+	}
+	return findings
+}
+
+// locateLeaf returns the byte offset of the leaf's value in the file, or -1 if it isn't found.
+// The same value can occur multiple times in a file, so offsets already used by other leaves are skipped
+// and an occurrence preceded by the leaf's name on the same line is preferred.
+func locateLeaf(leaf structured.Leaf, file string, used map[int]bool) int {
+	if leaf.Value == "" {
+		return -1
+	}
+
+	first := -1
+	for from := 0; from < len(file); {
+		i := strings.Index(file[from:], leaf.Value)
+		if i < 0 {
+			break
+		}
+		offset := from + i
+		from = offset + 1
+		if used[offset] {
+			continue
+		}
+		lineStart := strings.LastIndexByte(file[:offset], '\n') + 1
+		if strings.Contains(file[lineStart:offset], leaf.Name) {
+			return offset
+		}
+		if first < 0 {
+			first = offset
+		}
+	}
+	return first
+}
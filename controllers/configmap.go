@@ -2,17 +2,27 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
 	"github.com/lvlcn-t/secret-detection-operator/config"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -29,13 +39,21 @@ var _ reconcile.Reconciler = (*ConfigMapReconciler)(nil)
 // to a corresponding Secret and reports findings via the [v1alpha1.ExposedSecret] custom resource.
 type ConfigMapReconciler struct {
 	client.Client
-	scheme *runtime.Scheme
-	config *config.Config
+	scheme   *runtime.Scheme
+	config   *config.Config
+	recorder events.EventRecorder
+	// fingerprints maps the ConfigMaps to the fingerprint of their last successful reconciliation,
+	// see [ConfigMapReconciler.fingerprint].
+	fingerprints sync.Map
 }
 
 // NewConfigMapReconciler creates a new [ConfigMapReconciler].
-func NewConfigMapReconciler(c client.Client, s *runtime.Scheme, cfg *config.Config) *ConfigMapReconciler {
-	return &ConfigMapReconciler{Client: c, scheme: s, config: cfg}
+// The recorder emits Events regarding the reconciled resources; Events are discarded if it is nil.
+func NewConfigMapReconciler(c client.Client, s *runtime.Scheme, cfg *config.Config, rec events.EventRecorder) *ConfigMapReconciler {
+	if rec == nil {
+		rec = noopRecorder
+	}
+	return &ConfigMapReconciler{Client: c, scheme: s, config: cfg, recorder: rec}
 }
 
 // Reconcile scans the ConfigMap for secret-like keys and processes them according to a [v1alpha1.ScanPolicy].
@@ -50,9 +68,13 @@ func (r *ConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}()
 
 	log := logr.FromContextAsSlogLogger(ctx)
+	if !r.config.Watch.WatchesNamespace(req.Namespace) {
+		log.DebugContext(ctx, "Namespace not watched, skipping ConfigMap", "ConfigMap", req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
 	log.InfoContext(ctx, "Reconciling ConfigMap", "ConfigMap", req.NamespacedName)
 
-	policy, err := r.loadScanPolicy(ctx, req.Namespace)
+	policy, err := r.resolveScanPolicy(ctx, req.Namespace)
 	if err != nil {
 		ReconcileErrors.WithLabelValues(namespace, stageLoadPolicy).Inc()
 		log.ErrorContext(ctx, "Failed to get ScanPolicy", "error", err)
@@ -63,48 +85,176 @@ func (r *ConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if err = r.Get(ctx, req.NamespacedName, &cfgMap); err != nil {
 		if !errors.IsNotFound(err) {
 			ReconcileErrors.WithLabelValues(namespace, stageGetConfigMap).Inc()
+			log.WarnContext(ctx, "Failed to get ConfigMap", "error", err)
+			return ctrl.Result{}, err
+		}
+
+		log.DebugContext(ctx, "ConfigMap not found, resolving its ExposedSecrets")
+		r.fingerprints.Delete(req.NamespacedName)
+		r.recordScanPolicy(ctx, policy)
+		rc := newRecCtx(r.Client, r.recorder, policy, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Name: req.Name}})
+		rc.resolvedTTL = r.config.ResolvedTTL
+		if err = rc.resolveDeleted(ctx); err != nil {
+			ReconcileErrors.WithLabelValues(namespace, stageResolve).Inc()
+			return ctrl.Result{}, err
 		}
-		log.WarnContext(ctx, "Failed to get ConfigMap", "error", err)
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		return ctrl.Result{RequeueAfter: rc.requeueAfter}, nil
 	}
 
-	rc := newRecCtx(r.Client, policy, &cfgMap)
-	return ctrl.Result{}, rc.run(ctx)
-}
+	watched, err := r.inScope(ctx, &cfgMap)
+	if err != nil {
+		ReconcileErrors.WithLabelValues(namespace, stageGetConfigMap).Inc()
+		log.WarnContext(ctx, "Failed to check whether ConfigMap is watched", "error", err)
+		return ctrl.Result{}, err
+	}
+	if !watched {
+		log.DebugContext(ctx, "ConfigMap not watched, skipping it")
+		r.fingerprints.Delete(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
 
-// loadScanPolicy retrieves the ScanPolicy for the given namespace.
-// If no ScanPolicy is found, it returns a default policy.
-// If multiple policies are found, it uses the first one found.
-func (r *ConfigMapReconciler) loadScanPolicy(ctx context.Context, namespace string) (*v1alpha1.ScanPolicy, error) {
-	log := logr.FromContextAsSlogLogger(ctx)
-	var scanPolicies v1alpha1.ScanPolicyList
-	if err := r.List(ctx, &scanPolicies, client.InNamespace(namespace)); err != nil {
-		log.ErrorContext(ctx, "Failed to list ScanPolicies", "error", err)
-		return nil, err
+	ph, err := policyHash(policy)
+	if err != nil {
+		ReconcileErrors.WithLabelValues(namespace, stageLoadPolicy).Inc()
+		log.ErrorContext(ctx, "Failed to hash ScanPolicy", "error", err)
+		return ctrl.Result{}, err
 	}
+	if fp, ferr := r.fingerprint(ctx, &cfgMap, ph); ferr != nil {
+		log.WarnContext(ctx, "Failed to fingerprint ConfigMap", "error", ferr)
+	} else if cfgMap.DeletionTimestamp.IsZero() && r.unchanged(req.NamespacedName, fp) {
+		log.DebugContext(ctx, "ConfigMap and ScanPolicy unchanged, skipping reconciliation")
+		ReconcilesSkipped.WithLabelValues(namespace).Inc()
+		return ctrl.Result{RequeueAfter: r.requeueAfter(0)}, nil
+	}
+	r.fingerprints.Delete(req.NamespacedName)
+	r.recordScanPolicy(ctx, policy)
 
-	if len(scanPolicies.Items) == 0 {
-		log.DebugContext(ctx, "No ScanPolicies found, using default values")
-		return r.config.ScanPolicy.DeepCopy(), nil
+	rc := newRecCtx(r.Client, r.recorder, policy, &cfgMap)
+	rc.resolvedTTL = r.config.ResolvedTTL
+	rc.pepper = r.config.Pepper.Get()
+	rc.allowPlaintext = r.config.AllowPlaintext
+	if !cfgMap.DeletionTimestamp.IsZero() {
+		log.DebugContext(ctx, "ConfigMap is being deleted, releasing its ExposedSecrets")
+		if err = rc.release(ctx); err != nil {
+			ReconcileErrors.WithLabelValues(namespace, stageOwnership).Inc()
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if err = rc.run(ctx); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	// TODO: should we merge the policies with some merging strategy?
-	// Alternatively we could implement a webhook to ensure only one ScanPolicy per namespace.
-	if len(scanPolicies.Items) > 1 {
-		log.WarnContext(ctx, "Multiple ScanPolicies found, using the first one", "ScanPolicy", scanPolicies.Items[0].Name)
+	// Reconciliations with pending work (e.g. garbage collection) must not be skipped.
+	if rc.requeueAfter == 0 {
+		if fp, ferr := r.fingerprint(ctx, rc.configMap, ph); ferr != nil {
+			log.WarnContext(ctx, "Failed to fingerprint ConfigMap", "error", ferr)
+		} else {
+			r.fingerprints.Store(req.NamespacedName, scanned{fingerprint: fp, at: time.Now()})
+		}
 	}
+	return ctrl.Result{RequeueAfter: r.requeueAfter(rc.requeueAfter)}, nil
+}
 
-	sp := scanPolicies.Items[0].DeepCopy()
-	sp.Status.LastProcessedTime = metav1.Now()
-	if err := r.Status().Update(ctx, sp); err != nil {
-		log.ErrorContext(ctx, "Failed to update ScanPolicy status", "error", err)
+// requeueAfter returns the time after which the ConfigMap is reconciled again: the given time
+// needed by the reconciliation or the resync interval of the configuration, whichever is shorter.
+func (r *ConfigMapReconciler) requeueAfter(after time.Duration) time.Duration {
+	resync := r.config.ResyncInterval
+	if resync > 0 && (after == 0 || resync < after) {
+		return resync
 	}
-	return sp, nil
+	return after
 }
 
 // SetupWithManager registers this reconciler with the manager.
 func (r *ConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.ConfigMap{}).
-		Complete(r)
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.ExposedSecret{}, IndexExposedSecretConfigMap, IndexExposedSecretByConfigMap)
+	if err != nil {
+		return fmt.Errorf("failed to index ExposedSecrets: %w", err)
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(r.controllerOptions()).
+		For(&corev1.ConfigMap{}, builder.WithPredicates(r.scopePredicate())).
+		// Status updates don't change the generation, so only changes by the user (e.g. a revert) are watched.
+		Owns(&v1alpha1.ExposedSecret{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Policy changes affect every ConfigMap they apply to, so all of them are rescanned.
+		Watches(&v1alpha1.ScanPolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.mapScanPolicy),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		// Exceptions apply to the ConfigMaps in their namespace, so all of them are rescanned as well.
+		Watches(&v1alpha1.SecretException{},
+			handler.EnqueueRequestsFromMapFunc(r.mapScanPolicy),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+
+	// Cluster-scoped resources can't be watched with namespaced Roles.
+	if !r.config.Watch.Namespaced {
+		b = b.Watches(&v1alpha1.ClusterScanPolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.mapClusterScanPolicy),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+	}
+	if r.config.Watch.HasNamespaceSelector() {
+		b = b.Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.mapNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		)
+	}
+	return b.Complete(r)
+}
+
+// controllerOptions returns the concurrency and rate limiting options of the controller.
+// ConfigMaps are requeued after the longer delay of the per-item exponential backoff and the overall token bucket.
+func (r *ConfigMapReconciler) controllerOptions() controller.Options {
+	m := r.config.Manager
+	return controller.Options{
+		MaxConcurrentReconciles: m.MaxConcurrentReconciles,
+		RateLimiter: workqueue.NewTypedMaxOfRateLimiter(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](m.RateLimiter.BaseDelay, m.RateLimiter.MaxDelay),
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(m.RateLimiter.QPS), m.RateLimiter.Burst)},
+		),
+	}
+}
+
+// mapScanPolicy enqueues all ConfigMaps in the namespace of the [v1alpha1.ScanPolicy] or [v1alpha1.SecretException].
+func (r *ConfigMapReconciler) mapScanPolicy(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.configMapRequests(ctx, client.InNamespace(obj.GetNamespace()))
+}
+
+// mapClusterScanPolicy enqueues all ConfigMaps in namespaces without a [v1alpha1.ScanPolicy],
+// since a [v1alpha1.ClusterScanPolicy] only applies to them. All of them are enqueued, regardless
+// of the policy's selectors, because namespaces that no longer match have to be rescanned as well.
+func (r *ConfigMapReconciler) mapClusterScanPolicy(ctx context.Context, _ client.Object) []reconcile.Request {
+	log := logr.FromContextAsSlogLogger(ctx)
+	var policies v1alpha1.ScanPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		log.ErrorContext(ctx, "Failed to list ScanPolicies", "error", err)
+		return nil
+	}
+	covered := map[string]bool{}
+	for i := range policies.Items {
+		covered[policies.Items[i].Namespace] = true
+	}
+
+	requests := r.configMapRequests(ctx)
+	return slices.DeleteFunc(requests, func(req reconcile.Request) bool {
+		return covered[req.Namespace]
+	})
+}
+
+// configMapRequests returns a request for every ConfigMap matching the list options.
+func (r *ConfigMapReconciler) configMapRequests(ctx context.Context, opts ...client.ListOption) []reconcile.Request {
+	var list corev1.ConfigMapList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		logr.FromContextAsSlogLogger(ctx).ErrorContext(ctx, "Failed to list ConfigMaps", "error", err)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+	}
+	return requests
 }
@@ -2,17 +2,33 @@ package controllers_test
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"log/slog"
+	"slices"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"github.com/lvlcn-t/secret-detection-operator/config"
+	"github.com/lvlcn-t/secret-detection-operator/controllers"
 	"github.com/lvlcn-t/secret-detection-operator/scanners"
 	"github.com/lvlcn-t/secret-detection-operator/scanners/gitleaks"
 	"github.com/lvlcn-t/secret-detection-operator/test"
@@ -20,6 +36,11 @@ import (
 
 const secretValue = "my-secret"
 
+// esName returns the name of the ExposedSecret (and remediated Secret) of the ConfigMap key.
+func esName(cm, key string, subPaths ...string) string {
+	return v1alpha1.NewExposedSecretName(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: cm}}, key, subPaths...)
+}
+
 // TestReconcile_ScanPolicyListError verifies that listing policies errors out.
 func TestReconcile_ScanPolicyListError(t *testing.T) {
 	test.NewFramework(t).Unit(t).
@@ -88,7 +109,7 @@ func TestReconcile(t *testing.T) {
 				Data:       map[string]string{"password": secretValue},
 			},
 			want: &v1alpha1.ExposedSecret{
-				ObjectMeta: metav1.ObjectMeta{Namespace: "secret-detection-system", Name: "cm2-password"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "secret-detection-system", Name: esName("cm2", "password")},
 				Spec: v1alpha1.ExposedSecretSpec{
 					Action:   v1alpha1.ActionReportOnly,
 					Severity: test.DefaultScanner.DetectSeverity(secretValue),
@@ -97,6 +118,9 @@ func TestReconcile(t *testing.T) {
 					ConfigMapReference: v1alpha1.ConfigMapReference{Name: "cm2"},
 					Key:                "password",
 					Scanner:            test.DefaultScanner.Name(),
+					RuleID:             test.DefaultRuleID,
+					Line:               1,
+					MatchCount:         1,
 					DetectedValue:      v1alpha1.AlgorithmSHA256.Hash(secretValue),
 					Phase:              v1alpha1.PhaseDetected,
 				},
@@ -118,7 +142,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 			want: &v1alpha1.ExposedSecret{
-				ObjectMeta: metav1.ObjectMeta{Namespace: "secret-detection-system", Name: "cm3-password"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "secret-detection-system", Name: esName("cm3", "password")},
 				Spec: v1alpha1.ExposedSecretSpec{
 					Action:   v1alpha1.ActionIgnore,
 					Severity: scanners.SeverityUnknown,
@@ -148,7 +172,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 			want: &v1alpha1.ExposedSecret{
-				ObjectMeta: metav1.ObjectMeta{Namespace: "secret-detection-system", Name: "cm4-password"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "secret-detection-system", Name: esName("cm4", "password")},
 				Spec: v1alpha1.ExposedSecretSpec{
 					Action:   v1alpha1.ActionIgnore,
 					Severity: scanners.SeverityUnknown,
@@ -203,11 +227,11 @@ func TestReconcile_AutoRemediate(t *testing.T) {
 		},
 	}
 	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm-k"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esName("cm", "k")},
 		StringData: map[string]string{"k": secretValue},
 	}
 	es := &v1alpha1.ExposedSecret{
-		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm-k"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esName("cm", "k")},
 		Spec: v1alpha1.ExposedSecretSpec{
 			Action:   v1alpha1.ActionAutoRemediate,
 			Severity: test.DefaultScanner.DetectSeverity(secretValue),
@@ -218,7 +242,7 @@ func TestReconcile_AutoRemediate(t *testing.T) {
 			Scanner:            test.DefaultScanner.Name(),
 			DetectedValue:      v1alpha1.AlgorithmSHA256.Hash(secretValue),
 			Phase:              v1alpha1.PhaseRemediated,
-			CreatedSecretRef:   &v1alpha1.SecretReference{Name: "cm-k"},
+			CreatedSecretRef:   &v1alpha1.SecretReference{Name: esName("cm", "k")},
 		},
 	}
 
@@ -248,20 +272,21 @@ func TestReconcile_MultipleScanPolicies(t *testing.T) {
 	// ConfigMap with one secret‑like key
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
-		Data:       map[string]string{"k": secretValue},
+		Data:       map[string]string{"k": secretValue, "excluded": secretValue},
 	}
 
-	// First policy will ignore the exposed secret (audit only)
+	// First policy ignores exposed secrets with a high severity threshold
 	polA := &v1alpha1.ScanPolicy{
 		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "first"},
 		Spec: v1alpha1.ScanPolicySpec{
 			Action:        v1alpha1.ActionIgnore,
-			MinSeverity:   test.DefaultScanner.DetectSeverity(secretValue),
+			MinSeverity:   scanners.SeverityCritical,
 			Scanner:       test.DefaultScanner.Name(),
 			HashAlgorithm: v1alpha1.AlgorithmSHA256,
+			ExcludedKeys:  []string{"excluded"},
 		},
 	}
-	// Second policy would auto‑remediate if it were chosen
+	// Second policy auto-remediates with the lowest severity threshold
 	polB := &v1alpha1.ScanPolicy{
 		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "second"},
 		Spec: v1alpha1.ScanPolicySpec{
@@ -269,18 +294,31 @@ func TestReconcile_MultipleScanPolicies(t *testing.T) {
 			MinSeverity:   scanners.SeverityLow,
 			Scanner:       test.DefaultScanner.Name(),
 			HashAlgorithm: v1alpha1.AlgorithmSHA256,
+			ExcludedKeys:  []string{"other"},
 		},
 	}
+	wantSpec := v1alpha1.ScanPolicySpec{
+		Action:        v1alpha1.ActionAutoRemediate,
+		MinSeverity:   scanners.SeverityLow,
+		Scanner:       test.DefaultScanner.Name(),
+		HashAlgorithm: v1alpha1.AlgorithmSHA256,
+		ExcludedKeys:  []string{"excluded", "other"},
+	}
 
 	fw.Unit(t).
 		WithConfigMap(cm).
+		WithScanPolicy(polA).
+		WithScanPolicy(polB).
 		WithInterceptor(interceptor.Funcs{
 			List: func(ctx context.Context, c ctrlclient.WithWatch, list ctrlclient.ObjectList, opts ...ctrlclient.ListOption) error {
+				if err := c.List(ctx, list, opts...); err != nil {
+					return err
+				}
+				// The result must not depend on the order of the policies.
 				if lp, ok := list.(*v1alpha1.ScanPolicyList); ok {
-					lp.Items = []v1alpha1.ScanPolicy{*polA, *polB}
-					return nil
+					slices.Reverse(lp.Items)
 				}
-				return c.List(ctx, list, opts...)
+				return nil
 			},
 		}).
 		WithScanner(test.DefaultScanner).
@@ -290,19 +328,30 @@ func TestReconcile_MultipleScanPolicies(t *testing.T) {
 			require.NoError(t, u.Client.List(u.T.Context(), list))
 			require.Len(t, list.Items, 1)
 			test.AssertMatchesNonZeroFields(t, v1alpha1.ExposedSecret{
-				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm-k"},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "ns",
+					Name:        esName("cm", "k"),
+					Annotations: map[string]string{v1alpha1.AnnotationAppliedPolicy: "ScanPolicy/first,second"},
+				},
 				Spec: v1alpha1.ExposedSecretSpec{
-					Action:   v1alpha1.ActionIgnore,
-					Severity: scanners.SeverityUnknown,
+					Action:   v1alpha1.ActionAutoRemediate,
+					Severity: scanners.SeverityHigh,
 				},
 				Status: v1alpha1.ExposedSecretStatus{
 					ConfigMapReference: v1alpha1.ConfigMapReference{Name: "cm"},
 					Key:                "k",
 					Scanner:            test.DefaultScanner.Name(),
 					DetectedValue:      v1alpha1.AlgorithmSHA256.Hash(secretValue),
-					Phase:              v1alpha1.PhaseIgnored,
+					Phase:              v1alpha1.PhaseRemediated,
 				},
 			}, list.Items[0])
+
+			for _, name := range []string{"first", "second"} {
+				sp := &v1alpha1.ScanPolicy{}
+				require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: name}, sp))
+				require.Equal(t, &wantSpec, sp.Status.EffectiveSpec)
+				require.Equal(t, []string{"first", "second"}, sp.Status.MergedPolicies)
+			}
 		}).
 		Run()
 }
@@ -333,8 +382,8 @@ func TestReconcile_MultipleSecretKeys_ReportOnly(t *testing.T) {
 				require.Equal(t, v1alpha1.PhaseDetected, es.Status.Phase)
 				require.Equal(t, test.DefaultScanner.Name(), es.Status.Scanner)
 			}
-			require.Contains(t, names, "cm-k1")
-			require.Contains(t, names, "cm-k2")
+			require.Contains(t, names, esName("cm", "k1"))
+			require.Contains(t, names, esName("cm", "k2"))
 		}).
 		Run()
 }
@@ -367,7 +416,7 @@ func TestReconcile_AutoRemediate_WithConfigMapMutation(t *testing.T) {
 			secList := &corev1.SecretList{}
 			require.NoError(t, u.Client.List(u.T.Context(), secList))
 			require.Len(t, secList.Items, 1)
-			require.Equal(t, "cm-k", secList.Items[0].Name)
+			require.Equal(t, esName("cm", "k"), secList.Items[0].Name)
 		}).
 		WithAssertion(func(u *test.Unittest, _ ctrl.Result, err error) {
 			var updated corev1.ConfigMap
@@ -378,13 +427,89 @@ func TestReconcile_AutoRemediate_WithConfigMapMutation(t *testing.T) {
 			))
 			require.NotContains(t, updated.Data, "k")
 			require.Equal(t,
-				"cm-k",
+				esName("cm", "k"),
 				updated.Annotations[v1alpha1.AnnotationExposedSecret],
 			)
 		}).
 		Run()
 }
 
+func TestReconcile_RemediateOnAdmission(t *testing.T) {
+	pol := &v1alpha1.ScanPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol"},
+		Spec: v1alpha1.ScanPolicySpec{
+			Action:        v1alpha1.ActionReportOnly,
+			MinSeverity:   scanners.SeverityLow,
+			Scanner:       test.DefaultScanner.Name(),
+			HashAlgorithm: v1alpha1.AlgorithmSHA256,
+			AdmissionMode: v1alpha1.AdmissionRemediate,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		mode       v1alpha1.AdmissionMode
+		action     v1alpha1.Action
+		wantAction v1alpha1.Action
+		wantMoved  bool
+	}{
+		{name: "reported secrets are remediated", wantMoved: true},
+		{name: "user overrides are kept", action: v1alpha1.ActionIgnore, wantAction: v1alpha1.ActionIgnore},
+		{name: "mark is ignored if the policy doesn't remediate on admission", mode: v1alpha1.AdmissionWarn, wantAction: v1alpha1.ActionReportOnly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "ns",
+					Name:        "cm",
+					UID:         "cm-uid",
+					Annotations: map[string]string{v1alpha1.AnnotationRemediate: "true"},
+				},
+				Data: map[string]string{"k": secretValue, "plain": "hello"},
+			}
+			p := pol.DeepCopy()
+			if tt.mode != "" {
+				p.Spec.AdmissionMode = tt.mode
+			}
+			u := test.NewFramework(t).Unit(t).
+				WithConfigMap(cm).
+				WithScanPolicy(p).
+				WithScanner(test.DefaultScanner)
+			if tt.action != "" {
+				u = u.WithObjects(&v1alpha1.ExposedSecret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esName("cm", "k")},
+					Spec:       v1alpha1.ExposedSecretSpec{Action: tt.action},
+				})
+			}
+
+			u.WantError(false).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					var updated corev1.ConfigMap
+					require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(cm), &updated))
+					require.NotContains(t, updated.Annotations, v1alpha1.AnnotationRemediate)
+					require.Equal(t, "hello", updated.Data["plain"])
+
+					var es v1alpha1.ExposedSecret
+					require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}, &es))
+					err := u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}, &corev1.Secret{})
+					if !tt.wantMoved {
+						require.Equal(t, secretValue, updated.Data["k"])
+						require.Equal(t, tt.wantAction, es.Spec.Action)
+						require.True(t, apierrors.IsNotFound(err), "expected no Secret, got %v", err)
+						return
+					}
+					require.NotContains(t, updated.Data, "k")
+					require.Equal(t, esName("cm", "k"), updated.Annotations[v1alpha1.AnnotationExposedSecret])
+					require.Equal(t, v1alpha1.PhaseRemediated, es.Status.Phase)
+					require.NoError(t, err)
+				}).
+				Run()
+		})
+	}
+}
+
 func TestReconcile_AutoRemediate_MultipleKeys(t *testing.T) {
 	fw := test.NewFramework(t)
 
@@ -420,8 +545,8 @@ func TestReconcile_AutoRemediate_MultipleKeys(t *testing.T) {
 			for _, s := range secList.Items {
 				names[s.Name] = struct{}{}
 			}
-			require.Contains(t, names, "cm-k1")
-			require.Contains(t, names, "cm-k2")
+			require.Contains(t, names, esName("cm", "k1"))
+			require.Contains(t, names, esName("cm", "k2"))
 		}).
 		WithAssertion(func(u *test.Unittest, _ ctrl.Result, err error) {
 			exList := &v1alpha1.ExposedSecretList{}
@@ -433,8 +558,1767 @@ func TestReconcile_AutoRemediate_MultipleKeys(t *testing.T) {
 				names[ex.Name] = struct{}{}
 				require.Equal(t, v1alpha1.PhaseRemediated, ex.Status.Phase)
 			}
-			require.Contains(t, names, "cm-k1")
-			require.Contains(t, names, "cm-k2")
+			require.Contains(t, names, esName("cm", "k1"))
+			require.Contains(t, names, esName("cm", "k2"))
+		}).
+		Run()
+}
+
+func TestReconcile_BinaryData(t *testing.T) {
+	fw := test.NewFramework(t)
+	archive := test.NewTarGz(t, map[string]string{
+		"config/app.properties": "password=" + secretValue,
+		"README.md":             "nothing to see here",
+	})
+	// A JKS keystore (version 2) holding a private key entry, whose content is irrelevant for the detection.
+	keystore := []byte{0xfe, 0xed, 0xfe, 0xed, 0, 0, 0, 2, 0, 0, 0, 1, 0, 0, 0, 1, 0, 3, 'k', 'e', 'y', 0, 0, 0, 0, 0, 0, 0, 0}
+	// A JKS truststore (version 2) holding a single trusted certificate.
+	truststore := []byte{0xfe, 0xed, 0xfe, 0xed, 0, 0, 0, 2, 0, 0, 0, 1, 0, 0, 0, 2, 0, 2, 'c', 'a', 0, 0, 0, 0, 0, 0, 0, 0, 0, 5, 'X', '.', '5', '0', '9', 0, 0, 0, 1, 0}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+		BinaryData: map[string][]byte{
+			"bundle.tar.gz": archive,
+			"store.jks":     keystore,
+			"trust.jks":     truststore,
+		},
+	}
+	pol := &v1alpha1.ScanPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol"},
+		Spec: v1alpha1.ScanPolicySpec{
+			Action:                  v1alpha1.ActionAutoRemediate,
+			MinSeverity:             scanners.SeverityLow,
+			Scanner:                 test.DefaultScanner.Name(),
+			HashAlgorithm:           v1alpha1.AlgorithmSHA256,
+			EnableConfigMapMutation: true,
+		},
+	}
+
+	fw.Unit(t).
+		WithConfigMap(cm).
+		WithScanPolicy(pol).
+		WithScanner(test.DefaultScanner).
+		WantError(false).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, err error) {
+			exList := &v1alpha1.ExposedSecretList{}
+			require.NoError(t, u.Client.List(u.T.Context(), exList))
+			require.Len(t, exList.Items, 3)
+
+			got := map[string]v1alpha1.ExposedSecret{}
+			for _, es := range exList.Items {
+				got[es.Name] = es
+			}
+
+			es, ok := got[v1alpha1.NewExposedSecretName(cm, "bundle.tar.gz", "config/app.properties", "$.password")]
+			require.True(t, ok, "missing ExposedSecret for archive entry")
+			require.Equal(t, v1alpha1.SourceBinaryData, es.Status.Source)
+			require.Equal(t, "config/app.properties", es.Status.InnerPath)
+			require.Equal(t, "$.password", es.Status.Path)
+			require.Equal(t, v1alpha1.PhaseRemediated, es.Status.Phase)
+
+			es, ok = got[v1alpha1.NewExposedSecretName(cm, "store.jks")]
+			require.True(t, ok, "missing ExposedSecret for keystore")
+			require.Equal(t, v1alpha1.SourceBinaryData, es.Status.Source)
+			require.Equal(t, "jks-keystore", es.Status.RuleID)
+			require.Empty(t, es.Status.InnerPath)
+			require.Equal(t, scanners.SeverityHigh, es.Spec.Severity)
+
+			es, ok = got[v1alpha1.NewExposedSecretName(cm, "trust.jks")]
+			require.True(t, ok, "missing ExposedSecret for truststore")
+			require.Equal(t, "jks-truststore", es.Status.RuleID)
+			require.Equal(t, scanners.SeverityLow, es.Spec.Severity)
+		}).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, err error) {
+			var secret corev1.Secret
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "bundle.tar.gz")}, &secret))
+			require.Equal(t, archive, secret.Data["bundle.tar.gz"])
+
+			var updated corev1.ConfigMap
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: "cm"}, &updated))
+			require.Empty(t, updated.BinaryData)
+		}).
+		Run()
+}
+
+func TestReconcile_StructuredValues(t *testing.T) {
+	fw := test.NewFramework(t)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+		Data: map[string]string{
+			"application.yaml": "spring:\n  datasource:\n    url: jdbc:postgresql://db/app\n    password: " + secretValue + "\n",
+			"notes.txt":        "first line\n" + secretValue + " and " + secretValue + "\n",
+			"app.properties":   "db.user=app\ndb.password=" + secretValue + "\n",
+			".env":             "export TOKEN=\"" + secretValue + "\"\nDEBUG=true\n",
+			"raw":              secretValue,
+			"repeated.yaml":    "user: " + secretValue + "\npassword: " + secretValue + "\n",
+		},
+	}
+
+	fw.Unit(t).
+		WithConfigMap(cm).
+		WithScanner(test.DefaultScanner).
+		WantError(false).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, err error) {
+			exList := &v1alpha1.ExposedSecretList{}
+			require.NoError(t, u.Client.List(u.T.Context(), exList))
+
+			got := map[string]string{}
+			for _, es := range exList.Items {
+				if es.Status.Key != "notes.txt" {
+					require.Equal(t, v1alpha1.AlgorithmSHA256.Hash(secretValue), es.Status.DetectedValue)
+				}
+				got[es.Name] = es.Status.Location()
+			}
+			require.Equal(t, map[string]string{
+				v1alpha1.NewExposedSecretName(cm, "application.yaml", "$.spring.datasource.password"): "application.yaml:$.spring.datasource.password",
+				v1alpha1.NewExposedSecretName(cm, "app.properties", "$['db.password']"):               "app.properties:$['db.password']",
+				v1alpha1.NewExposedSecretName(cm, ".env", "$.TOKEN"):                                  ".env:$.TOKEN",
+				v1alpha1.NewExposedSecretName(cm, "raw"):                                              "raw",
+				v1alpha1.NewExposedSecretName(cm, "notes.txt"):                                        "notes.txt",
+				v1alpha1.NewExposedSecretName(cm, "repeated.yaml", "$.user"):                          "repeated.yaml:$.user",
+				v1alpha1.NewExposedSecretName(cm, "repeated.yaml", "$.password"):                      "repeated.yaml:$.password",
+			}, got)
+		}).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, err error) {
+			var es v1alpha1.ExposedSecret
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: v1alpha1.NewExposedSecretName(cm, "application.yaml", "$.spring.datasource.password")}, &es))
+			require.Equal(t, test.DefaultRuleID, es.Status.RuleID)
+			require.Equal(t, 4, es.Status.Line)
+			require.Equal(t, 1, es.Status.MatchCount)
+
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: v1alpha1.NewExposedSecretName(cm, "notes.txt")}, &es))
+			require.Equal(t, 2, es.Status.Line)
+			require.Equal(t, 2, es.Status.MatchCount)
+
+			// Repeated values are located at their own lines.
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: v1alpha1.NewExposedSecretName(cm, "repeated.yaml", "$.user")}, &es))
+			require.Equal(t, 1, es.Status.Line)
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: v1alpha1.NewExposedSecretName(cm, "repeated.yaml", "$.password")}, &es))
+			require.Equal(t, 2, es.Status.Line)
+		}).
+		Run()
+}
+
+// TestReconcile_ClusterScanPolicy verifies that the matching ClusterScanPolicy
+// with the highest priority is applied if the namespace has no ScanPolicy.
+func TestReconcile_ClusterScanPolicy(t *testing.T) {
+	spec := func(action v1alpha1.Action) v1alpha1.ScanPolicySpec {
+		return v1alpha1.ScanPolicySpec{
+			Action:        action,
+			MinSeverity:   scanners.SeverityLow,
+			Scanner:       test.DefaultScanner.Name(),
+			HashAlgorithm: v1alpha1.AlgorithmSHA256,
+		}
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tier": "prod"}}}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "cm"},
+		Data:       map[string]string{"k": secretValue},
+	}
+
+	tests := []struct {
+		name       string
+		policies   []*v1alpha1.ClusterScanPolicy
+		scanPolicy *v1alpha1.ScanPolicy
+		wantSource string
+		wantAction v1alpha1.Action
+	}{
+		{
+			name: "highest priority wins",
+			policies: []*v1alpha1.ClusterScanPolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "low"},
+					Spec:       v1alpha1.ClusterScanPolicySpec{ScanPolicySpec: spec(v1alpha1.ActionIgnore), Priority: 1},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "high"},
+					Spec: v1alpha1.ClusterScanPolicySpec{
+						ScanPolicySpec:    spec(v1alpha1.ActionReportOnly),
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "prod"}},
+						Priority:          10,
+					},
+				},
+			},
+			wantSource: "ClusterScanPolicy/high",
+			wantAction: v1alpha1.ActionReportOnly,
+		},
+		{
+			name: "non-matching policies are skipped",
+			policies: []*v1alpha1.ClusterScanPolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "other-team"},
+					Spec: v1alpha1.ClusterScanPolicySpec{
+						ScanPolicySpec: spec(v1alpha1.ActionReportOnly),
+						Namespaces:     []string{"team-b*"},
+						Priority:       10,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+					Spec: v1alpha1.ClusterScanPolicySpec{
+						ScanPolicySpec:    spec(v1alpha1.ActionReportOnly),
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "staging"}},
+						Priority:          5,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+					Spec: v1alpha1.ClusterScanPolicySpec{
+						ScanPolicySpec: spec(v1alpha1.ActionIgnore),
+						Namespaces:     []string{"team-a*"},
+					},
+				},
+			},
+			wantSource: "ClusterScanPolicy/team-a",
+			wantAction: v1alpha1.ActionIgnore,
+		},
+		{
+			name: "namespaced policy takes precedence",
+			policies: []*v1alpha1.ClusterScanPolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "all"},
+					Spec:       v1alpha1.ClusterScanPolicySpec{ScanPolicySpec: spec(v1alpha1.ActionIgnore), Priority: 100},
+				},
+			},
+			scanPolicy: &v1alpha1.ScanPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "local"},
+				Spec:       spec(v1alpha1.ActionReportOnly),
+			},
+			wantSource: "ScanPolicy/local",
+			wantAction: v1alpha1.ActionReportOnly,
+		},
+		{
+			name:       "default policy without any match",
+			wantSource: v1alpha1.DefaultPolicySource,
+			wantAction: v1alpha1.ActionReportOnly,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit := test.NewFramework(t).Unit(t).
+				WithNamespace(ns.DeepCopy()).
+				WithConfigMap(cm.DeepCopy()).
+				WithScanPolicy(tt.scanPolicy).
+				WithScanner(test.DefaultScanner)
+			for _, p := range tt.policies {
+				unit = unit.WithClusterScanPolicy(p)
+			}
+
+			unit.WithAssertion(func(u *test.Unittest, _ ctrl.Result, err error) {
+				require.NoError(t, err)
+				es := &v1alpha1.ExposedSecret{}
+				require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "team-a", Name: esName("cm", "k")}, es))
+				require.Equal(t, tt.wantSource, es.Annotations[v1alpha1.AnnotationAppliedPolicy])
+				require.Equal(t, tt.wantAction, es.Spec.Action)
+			}).Run()
+		})
+	}
+}
+
+func TestReconcile_AutoRemediate_WorkloadRewiring(t *testing.T) {
+	fw := test.NewFramework(t)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+		Data:       map[string]string{"k": secretValue, "other": "plain"},
+	}
+	pol := &v1alpha1.ScanPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol"},
+		Spec: v1alpha1.ScanPolicySpec{
+			Action:                  v1alpha1.ActionAutoRemediate,
+			MinSeverity:             scanners.SeverityLow,
+			Scanner:                 test.DefaultScanner.Name(),
+			EnableConfigMapMutation: true,
+			EnableWorkloadRewiring:  true,
+		},
+	}
+
+	keyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "cm"},
+			Key:                  key,
+		}}
+	}
+	podSpec := func() corev1.PodSpec {
+		return corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "app",
+				Env: []corev1.EnvVar{
+					{Name: "SECRET", ValueFrom: keyRef("k")},
+					{Name: "OTHER", ValueFrom: keyRef("other")},
+				},
+				EnvFrom: []corev1.EnvFromSource{{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}},
+				}},
+			}},
+			Volumes: []corev1.Volume{
+				{Name: "all", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "cm"},
+				}}},
+				{Name: "items", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "cm"},
+					Items:                []corev1.KeyToPath{{Key: "k", Path: "secret.txt"}, {Key: "other", Path: "other.txt"}},
+				}}},
+				{Name: "unrelated", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		}
+	}
+
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web"},
+		Spec:       appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: podSpec()}},
+	}
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backup"},
+		Spec: batchv1.CronJobSpec{JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name: "backup",
+				Env:  []corev1.EnvVar{{Name: "SECRET", ValueFrom: keyRef("k")}},
+			}}}},
+		}}},
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "migrate"},
+		Spec:       batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: podSpec()}},
+	}
+	unrelated := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "db"},
+		Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "db",
+			Env:  []corev1.EnvVar{{Name: "OTHER", ValueFrom: keyRef("other")}},
+		}}}}},
+	}
+
+	secretRef := corev1.LocalObjectReference{Name: esName("cm", "k")}
+	fw.Unit(t).
+		WithConfigMap(cm).
+		WithScanPolicy(pol).
+		WithObjects(deploy, cronJob, job, unrelated).
+		WithScanner(test.DefaultScanner).
+		WantError(false).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+			var got appsv1.Deployment
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(deploy), &got))
+			spec := got.Spec.Template.Spec
+
+			ctr := spec.Containers[0]
+			require.Equal(t, &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: secretRef, Key: "k"}}, ctr.Env[0].ValueFrom)
+			require.Equal(t, keyRef("other"), ctr.Env[1].ValueFrom)
+			require.Equal(t, []corev1.EnvFromSource{
+				{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}}},
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: secretRef}},
+			}, ctr.EnvFrom)
+
+			require.Equal(t, &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}}},
+				{Secret: &corev1.SecretProjection{LocalObjectReference: secretRef, Items: []corev1.KeyToPath{{Key: "k", Path: "k"}}}},
+			}}, spec.Volumes[0].Projected)
+			require.Equal(t, &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "cm"},
+					Items:                []corev1.KeyToPath{{Key: "other", Path: "other.txt"}},
+				}},
+				{Secret: &corev1.SecretProjection{LocalObjectReference: secretRef, Items: []corev1.KeyToPath{{Key: "k", Path: "secret.txt"}}}},
+			}}, spec.Volumes[1].Projected)
+			require.NotNil(t, spec.Volumes[2].EmptyDir)
+		}).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+			var gotCronJob batchv1.CronJob
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(cronJob), &gotCronJob))
+			require.NotNil(t, gotCronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env[0].ValueFrom.SecretKeyRef)
+
+			// Job templates are immutable, so the Job is left untouched.
+			var gotJob batchv1.Job
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(job), &gotJob))
+			require.Equal(t, job.Spec.Template.Spec, gotJob.Spec.Template.Spec)
+
+			var gotSts appsv1.StatefulSet
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(unrelated), &gotSts))
+			require.Equal(t, unrelated.Spec.Template.Spec, gotSts.Spec.Template.Spec)
+		}).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+			var es v1alpha1.ExposedSecret
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}, &es))
+			require.Equal(t, v1alpha1.PhaseRemediated, es.Status.Phase)
+			require.Equal(t, []v1alpha1.WorkloadReference{
+				{Kind: "CronJob", Name: "backup"},
+				{Kind: "Deployment", Name: "web"},
+			}, es.Status.RewiredWorkloads)
+
+			var updated corev1.ConfigMap
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(cm), &updated))
+			require.NotContains(t, updated.Data, "k")
 		}).
 		Run()
 }
+
+func TestReconcile_Revert(t *testing.T) {
+	tests := []struct {
+		name         string
+		deleteSecret bool
+	}{
+		{name: "keeps the secret", deleteSecret: false},
+		{name: "deletes the secret", deleteSecret: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fw := test.NewFramework(t)
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "ns",
+					Name:        "cm",
+					Annotations: map[string]string{v1alpha1.AnnotationExposedSecret: esName("cm", "app.yaml")},
+				},
+				Data: map[string]string{"plain": "value"},
+			}
+			pol := &v1alpha1.ScanPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol"},
+				Spec: v1alpha1.ScanPolicySpec{
+					Action:                  v1alpha1.ActionAutoRemediate,
+					MinSeverity:             scanners.SeverityLow,
+					Scanner:                 test.DefaultScanner.Name(),
+					EnableConfigMapMutation: true,
+				},
+			}
+			value := "password: " + secretValue + "\ntoken: " + secretValue + "\n"
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: v1alpha1.NewExposedSecretName(cm, "app.yaml")},
+				Data:       map[string][]byte{"app.yaml": []byte(value)},
+			}
+			remediated := func(path string, action v1alpha1.Action) *v1alpha1.ExposedSecret {
+				return &v1alpha1.ExposedSecret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: v1alpha1.NewExposedSecretName(cm, "app.yaml", path)},
+					Spec:       v1alpha1.ExposedSecretSpec{Action: action, DeleteSecretOnRevert: tt.deleteSecret},
+					Status: v1alpha1.ExposedSecretStatus{
+						ConfigMapReference: v1alpha1.ConfigMapReference{Name: "cm"},
+						Key:                "app.yaml",
+						Path:               path,
+						Phase:              v1alpha1.PhaseRemediated,
+						CreatedSecretRef:   &v1alpha1.SecretReference{Name: secret.Name},
+					},
+				}
+			}
+			// Both ExposedSecrets share the Secret, because they were found in the same structured value.
+			reverted := remediated("$.password", v1alpha1.ActionRevert)
+			sibling := remediated("$.token", v1alpha1.ActionAutoRemediate)
+
+			fw.Unit(t).
+				WithConfigMap(cm).
+				WithScanPolicy(pol).
+				WithObjects(secret, reverted, sibling).
+				WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					var updated corev1.ConfigMap
+					require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(cm), &updated))
+					require.Equal(t, map[string]string{"plain": "value", "app.yaml": value}, updated.Data)
+					require.NotContains(t, updated.Annotations, v1alpha1.AnnotationExposedSecret)
+
+					err := u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(secret), &corev1.Secret{})
+					if tt.deleteSecret {
+						require.True(t, apierrors.IsNotFound(err), "expected the Secret to be deleted, got %v", err)
+					} else {
+						require.NoError(t, err)
+					}
+				}).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					for _, name := range []string{reverted.Name, sibling.Name} {
+						var es v1alpha1.ExposedSecret
+						require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: name}, &es))
+						require.Equal(t, v1alpha1.ActionRevert, es.Spec.Action, name)
+						require.Equal(t, v1alpha1.PhaseReverted, es.Status.Phase, name)
+						require.Nil(t, es.Status.CreatedSecretRef, name)
+					}
+				}).
+				Run()
+		})
+	}
+}
+
+func TestReconcile_Resolve(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+		Data:       map[string]string{"plain": "value", "k": secretValue},
+	}
+	exposed := func(key string, phase v1alpha1.Phase, resolved time.Duration) *v1alpha1.ExposedSecret {
+		es := &v1alpha1.ExposedSecret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: v1alpha1.NewExposedSecretName(cm, key)},
+			Spec:       v1alpha1.ExposedSecretSpec{Action: v1alpha1.ActionReportOnly},
+			Status: v1alpha1.ExposedSecretStatus{
+				ConfigMapReference: v1alpha1.ConfigMapReference{Name: cm.Name},
+				Key:                key,
+				Phase:              phase,
+			},
+		}
+		if resolved > 0 {
+			es.Status.ResolvedTime = &metav1.Time{Time: time.Now().Add(-resolved)}
+		}
+		return es
+	}
+
+	tests := []struct {
+		name        string
+		deleted     bool
+		ttl         string
+		objects     []ctrlclient.Object
+		wantPhases  map[string]v1alpha1.Phase
+		wantRequeue bool
+	}{
+		{
+			name: "key removed",
+			objects: []ctrlclient.Object{
+				exposed("k", v1alpha1.PhaseDetected, 0),
+				exposed("gone", v1alpha1.PhaseDetected, 0),
+				exposed("ignored", v1alpha1.PhaseIgnored, 0),
+				exposed("moved", v1alpha1.PhaseRemediated, 0),
+			},
+			wantPhases: map[string]v1alpha1.Phase{
+				esName("cm", "k"):       v1alpha1.PhaseDetected,
+				esName("cm", "gone"):    v1alpha1.PhaseResolved,
+				esName("cm", "ignored"): v1alpha1.PhaseResolved,
+				esName("cm", "moved"):   v1alpha1.PhaseRemediated,
+			},
+		},
+		{
+			name:    "configmap deleted",
+			deleted: true,
+			objects: []ctrlclient.Object{
+				exposed("k", v1alpha1.PhaseDetected, 0),
+				exposed("moved", v1alpha1.PhaseRemediated, 0),
+			},
+			wantPhases: map[string]v1alpha1.Phase{
+				esName("cm", "k"):     v1alpha1.PhaseResolved,
+				esName("cm", "moved"): v1alpha1.PhaseRemediated,
+			},
+		},
+		{
+			name: "resolved findings are garbage collected",
+			ttl:  "1h",
+			objects: []ctrlclient.Object{
+				exposed("expired", v1alpha1.PhaseResolved, 2*time.Hour),
+				exposed("recent", v1alpha1.PhaseResolved, 10*time.Minute),
+			},
+			wantPhases: map[string]v1alpha1.Phase{
+				esName("cm", "k"):      v1alpha1.PhaseDetected,
+				esName("cm", "recent"): v1alpha1.PhaseResolved,
+			},
+			wantRequeue: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fw := test.NewFramework(t)
+			u := fw.Unit(t)
+			if tt.ttl != "" {
+				cfg, err := config.LoadFS("config.yaml", fstest.MapFS{
+					"config.yaml": &fstest.MapFile{Data: []byte("resolvedTTL: " + tt.ttl + "\n")},
+				})
+				require.NoError(t, err)
+				u = u.WithConfig(cfg)
+			}
+			if tt.deleted {
+				u = u.WithDeletedConfigMap(cm)
+			} else {
+				u = u.WithConfigMap(cm)
+			}
+
+			u.WithObjects(tt.objects...).
+				WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(u *test.Unittest, r ctrl.Result, _ error) {
+					exList := &v1alpha1.ExposedSecretList{}
+					require.NoError(t, u.Client.List(u.T.Context(), exList))
+
+					got := map[string]v1alpha1.Phase{}
+					for _, es := range exList.Items {
+						got[es.Name] = es.Status.Phase
+						if es.Status.Phase == v1alpha1.PhaseResolved {
+							require.NotNil(t, es.Status.ResolvedTime, es.Name)
+						}
+					}
+					require.Equal(t, tt.wantPhases, got)
+
+					if tt.wantRequeue {
+						require.InDelta(t, 50*time.Minute, r.RequeueAfter, float64(time.Minute))
+					} else {
+						require.Zero(t, r.RequeueAfter)
+					}
+				}).
+				Run()
+		})
+	}
+}
+
+func TestReconcile_Ownership(t *testing.T) {
+	pol := &v1alpha1.ScanPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol"},
+		Spec: v1alpha1.ScanPolicySpec{
+			Action:           v1alpha1.ActionAutoRemediate,
+			MinSeverity:      scanners.SeverityLow,
+			Scanner:          test.DefaultScanner.Name(),
+			HashAlgorithm:    v1alpha1.AlgorithmSHA256,
+			FindingRetention: v1alpha1.RetentionRetain,
+		},
+	}
+
+	t.Run("resources are owned", func(t *testing.T) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", UID: "cm-uid"},
+			Data:       map[string]string{"k": secretValue},
+		}
+
+		test.NewFramework(t).Unit(t).
+			WithConfigMap(cm).
+			WithScanPolicy(pol).
+			WithScanner(test.DefaultScanner).
+			WantError(false).
+			WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+				var es v1alpha1.ExposedSecret
+				require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}, &es))
+				owner := metav1.GetControllerOf(&es)
+				require.NotNil(t, owner)
+				require.Equal(t, "ConfigMap", owner.Kind)
+				require.Equal(t, cm.UID, owner.UID)
+
+				var secret corev1.Secret
+				require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}, &secret))
+				require.Len(t, secret.OwnerReferences, 1)
+				require.Equal(t, "ExposedSecret", secret.OwnerReferences[0].Kind)
+				require.Equal(t, es.UID, secret.OwnerReferences[0].UID)
+				require.Equal(t, esName("cm", "k"), secret.Labels[v1alpha1.LabelExposedSecret])
+				require.Equal(t, v1alpha1.ManagedBy, secret.Labels[v1alpha1.LabelManagedBy])
+
+				var updated corev1.ConfigMap
+				require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(cm), &updated))
+				require.Contains(t, updated.Finalizers, v1alpha1.FinalizerRetainFindings)
+			}).
+			Run()
+	})
+
+	t.Run("second reconcile keeps ownership", func(t *testing.T) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", UID: "cm-uid"},
+			Data:       map[string]string{"k": secretValue},
+		}
+		key := ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}
+		// armed fails every update of the resources dropping their owner references, once the first reconcile is done.
+		armed := false
+		var stripped []string
+
+		test.NewFramework(t).Unit(t).
+			WithConfigMap(cm).
+			WithScanPolicy(pol).
+			WithScanner(test.DefaultScanner).
+			WithInterceptor(interceptor.Funcs{
+				Update: func(ctx context.Context, c ctrlclient.WithWatch, obj ctrlclient.Object, opts ...ctrlclient.UpdateOption) error {
+					switch obj.(type) {
+					case *v1alpha1.ExposedSecret, *corev1.Secret:
+						if armed && len(obj.GetOwnerReferences()) == 0 {
+							stripped = append(stripped, obj.GetName())
+						}
+					}
+					return c.Update(ctx, obj, opts...)
+				},
+			}).
+			WantError(false).
+			WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+				var es v1alpha1.ExposedSecret
+				require.NoError(t, u.Client.Get(u.T.Context(), key, &es))
+				es.Labels = map[string]string{"team": "a"}
+				es.Annotations["note"] = "user"
+				require.NoError(t, u.Client.Update(u.T.Context(), &es))
+				var secret corev1.Secret
+				require.NoError(t, u.Client.Get(u.T.Context(), key, &secret))
+				secret.Labels["team"] = "a"
+				require.NoError(t, u.Client.Update(u.T.Context(), &secret))
+
+				// Change the data, so the ConfigMap isn't skipped as unchanged.
+				updateConfigMap(t, u, func(cm *corev1.ConfigMap) { cm.Data["other"] = "value" })
+				armed = true
+				ctx := logr.NewContextWithSlogLogger(u.T.Context(), slog.Default())
+				_, err := u.Reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: ctrlclient.ObjectKeyFromObject(cm)})
+				require.NoError(t, err)
+				require.Empty(t, stripped, "updates must not strip the owner references")
+
+				var gotES v1alpha1.ExposedSecret
+				require.NoError(t, u.Client.Get(u.T.Context(), key, &gotES))
+				require.Equal(t, es.OwnerReferences, gotES.OwnerReferences)
+				require.Equal(t, es.Labels, gotES.Labels)
+				require.Equal(t, "user", gotES.Annotations["note"])
+
+				var gotSecret corev1.Secret
+				require.NoError(t, u.Client.Get(u.T.Context(), key, &gotSecret))
+				require.Equal(t, secret.OwnerReferences, gotSecret.OwnerReferences)
+				require.Equal(t, secret.Labels, gotSecret.Labels)
+			}).
+			Run()
+	})
+
+	t.Run("retained findings are released", func(t *testing.T) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "ns",
+				Name:              "cm",
+				UID:               "cm-uid",
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				Finalizers:        []string{v1alpha1.FinalizerRetainFindings},
+			},
+			Data: map[string]string{"k": secretValue},
+		}
+		es := &v1alpha1.ExposedSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      esName("cm", "k"),
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       cm.Name,
+					UID:        cm.UID,
+					Controller: ptr.To(true),
+				}},
+			},
+			Spec: v1alpha1.ExposedSecretSpec{Action: v1alpha1.ActionReportOnly},
+			Status: v1alpha1.ExposedSecretStatus{
+				ConfigMapReference: v1alpha1.ConfigMapReference{Name: cm.Name},
+				Key:                "k",
+				Phase:              v1alpha1.PhaseDetected,
+			},
+		}
+
+		test.NewFramework(t).Unit(t).
+			WithConfigMap(cm).
+			WithObjects(es).
+			WithScanPolicy(pol).
+			WithScanner(test.DefaultScanner).
+			WantError(false).
+			WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+				var got v1alpha1.ExposedSecret
+				require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(es), &got))
+				require.Empty(t, got.OwnerReferences)
+				require.Equal(t, v1alpha1.PhaseResolved, got.Status.Phase)
+
+				err := u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+				require.True(t, apierrors.IsNotFound(err), "ConfigMap should be deleted once the finalizer is removed")
+			}).
+			Run()
+	})
+}
+
+func TestReconcile_Naming(t *testing.T) {
+	t.Run("similar keys don't collide", func(t *testing.T) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+			Data:       map[string]string{"db.password": secretValue, "db_password": secretValue},
+		}
+
+		test.NewFramework(t).Unit(t).
+			WithConfigMap(cm).
+			WithScanner(test.DefaultScanner).
+			WantError(false).
+			WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+				exList := &v1alpha1.ExposedSecretList{}
+				require.NoError(t, u.Client.List(u.T.Context(), exList))
+				require.Len(t, exList.Items, 2)
+				for _, es := range exList.Items {
+					require.Equal(t, esName("cm", es.Status.Key), es.Name)
+					require.Equal(t, es.Status.Key, es.Annotations[v1alpha1.AnnotationKey])
+				}
+			}).
+			Run()
+	})
+
+	t.Run("legacy names are migrated", func(t *testing.T) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+			Data:       map[string]string{"plain": "value"},
+		}
+		legacy := &v1alpha1.ExposedSecret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm-k"},
+			Spec:       v1alpha1.ExposedSecretSpec{Action: v1alpha1.ActionAutoRemediate, Notes: "rotated"},
+			Status: v1alpha1.ExposedSecretStatus{
+				ConfigMapReference: v1alpha1.ConfigMapReference{Name: cm.Name},
+				Key:                "k",
+				Phase:              v1alpha1.PhaseRemediated,
+				CreatedSecretRef:   &v1alpha1.SecretReference{Name: "cm-k"},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm-k"},
+			Data:       map[string][]byte{"k": []byte(secretValue)},
+		}
+
+		test.NewFramework(t).Unit(t).
+			WithConfigMap(cm).
+			WithObjects(legacy, secret).
+			WithScanner(test.DefaultScanner).
+			WantError(false).
+			WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+				err := u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(legacy), &v1alpha1.ExposedSecret{})
+				require.True(t, apierrors.IsNotFound(err), "legacy ExposedSecret should be deleted")
+
+				var es v1alpha1.ExposedSecret
+				require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}, &es))
+				require.Equal(t, "rotated", es.Spec.Notes)
+				require.Equal(t, "k", es.Annotations[v1alpha1.AnnotationKey])
+				require.Equal(t, v1alpha1.PhaseRemediated, es.Status.Phase)
+				require.Equal(t, &v1alpha1.SecretReference{Name: "cm-k"}, es.Status.CreatedSecretRef)
+
+				var got corev1.Secret
+				require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(secret), &got))
+				require.Len(t, got.OwnerReferences, 1)
+				require.Equal(t, es.Name, got.OwnerReferences[0].Name)
+			}).
+			Run()
+	})
+}
+
+func TestReconcile_Events(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     v1alpha1.Action
+		gitleaks   *v1alpha1.GitleaksConfig
+		wantEvents []string
+	}{
+		{
+			name:   "detected secret",
+			action: v1alpha1.ActionReportOnly,
+			wantEvents: []string{
+				"Warning " + controllers.ReasonSecretDetected,
+				"Normal " + controllers.ReasonPhaseChanged + " Phase changed to Detected",
+			},
+		},
+		{
+			name:   "remediated secret",
+			action: v1alpha1.ActionAutoRemediate,
+			wantEvents: []string{
+				"Normal " + controllers.ReasonConfigMapMutated,
+				"Normal " + controllers.ReasonSecretRemediated,
+				"Normal " + controllers.ReasonPhaseChanged + " Phase changed to Remediated",
+			},
+		},
+		{
+			name:   "invalid rules",
+			action: v1alpha1.ActionReportOnly,
+			gitleaks: &v1alpha1.GitleaksConfig{
+				Rules: []gitleaks.Rule{{ID: "broken", Regex: "(", Entropy: "1"}},
+			},
+			wantEvents: []string{"Warning " + controllers.ReasonInvalidRules},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"k": secretValue},
+			}
+			pol := &v1alpha1.ScanPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol"},
+				Spec: v1alpha1.ScanPolicySpec{
+					Action:                  tt.action,
+					MinSeverity:             scanners.SeverityLow,
+					Scanner:                 test.DefaultScanner.Name(),
+					HashAlgorithm:           v1alpha1.AlgorithmSHA256,
+					EnableConfigMapMutation: true,
+					GitleaksConfig:          tt.gitleaks,
+				},
+			}
+
+			test.NewFramework(t).Unit(t).
+				WithConfigMap(cm).
+				WithScanPolicy(pol).
+				WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					close(u.Recorder.Events)
+					var got []string
+					for e := range u.Recorder.Events {
+						require.NotContains(t, e, secretValue, "Events must not include secret values")
+						got = append(got, e)
+					}
+					require.Len(t, got, len(tt.wantEvents), "got events %v", got)
+					for i, want := range tt.wantEvents {
+						require.True(t, strings.HasPrefix(got[i], want), "event %q should start with %q", got[i], want)
+					}
+				}).
+				Run()
+		})
+	}
+}
+
+func TestReconcile_PolicyEventsOnce(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+		Data:       map[string]string{"k": secretValue},
+	}
+	other := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other"},
+		Data:       map[string]string{"plain": "hello"},
+	}
+	pol := &v1alpha1.ScanPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol", Generation: 1},
+		Spec: v1alpha1.ScanPolicySpec{
+			Action:         v1alpha1.ActionReportOnly,
+			MinSeverity:    scanners.SeverityLow,
+			Scanner:        test.DefaultScanner.Name(),
+			HashAlgorithm:  v1alpha1.AlgorithmSHA256,
+			GitleaksConfig: &v1alpha1.GitleaksConfig{Rules: []gitleaks.Rule{{ID: "broken", Regex: "("}}},
+		},
+	}
+
+	test.NewFramework(t).Unit(t).
+		WithConfigMap(cm).
+		WithObjects(other).
+		WithScanPolicy(pol).
+		WithScanner(test.DefaultScanner).
+		WantError(false).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+			var before v1alpha1.ScanPolicy
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(pol), &before))
+
+			ctx := logr.NewContextWithSlogLogger(u.T.Context(), slog.Default())
+			for _, name := range []string{"other", "cm"} {
+				_, err := u.Reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: ctrlclient.ObjectKey{Namespace: "ns", Name: name}})
+				require.NoError(t, err)
+			}
+
+			var after v1alpha1.ScanPolicy
+			require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(pol), &after))
+			require.Equal(t, before.ResourceVersion, after.ResourceVersion, "unchanged status must not be updated")
+
+			close(u.Recorder.Events)
+			var invalid int
+			for e := range u.Recorder.Events {
+				if strings.Contains(e, controllers.ReasonInvalidRules) {
+					invalid++
+				}
+			}
+			require.Equal(t, 1, invalid)
+		}).
+		Run()
+}
+
+func TestReconcile_Conditions(t *testing.T) {
+	tests := []struct {
+		name             string
+		action           v1alpha1.Action
+		gitleaks         *v1alpha1.GitleaksConfig
+		wantExposed      map[string]metav1.ConditionStatus
+		wantPolicy       map[string]metav1.ConditionStatus
+		wantPolicyReason string
+	}{
+		{
+			name:   "exposed secret",
+			action: v1alpha1.ActionReportOnly,
+			wantExposed: map[string]metav1.ConditionStatus{
+				v1alpha1.ConditionReady:      metav1.ConditionTrue,
+				v1alpha1.ConditionRemediated: metav1.ConditionFalse,
+				v1alpha1.ConditionDegraded:   metav1.ConditionTrue,
+			},
+			wantPolicy: map[string]metav1.ConditionStatus{
+				v1alpha1.ConditionReady:      metav1.ConditionTrue,
+				v1alpha1.ConditionRulesValid: metav1.ConditionTrue,
+				v1alpha1.ConditionDegraded:   metav1.ConditionFalse,
+			},
+			wantPolicyReason: v1alpha1.ReasonValid,
+		},
+		{
+			name:   "remediated secret",
+			action: v1alpha1.ActionAutoRemediate,
+			wantExposed: map[string]metav1.ConditionStatus{
+				v1alpha1.ConditionReady:      metav1.ConditionTrue,
+				v1alpha1.ConditionRemediated: metav1.ConditionTrue,
+				v1alpha1.ConditionDegraded:   metav1.ConditionFalse,
+			},
+			wantPolicy: map[string]metav1.ConditionStatus{
+				v1alpha1.ConditionReady:      metav1.ConditionTrue,
+				v1alpha1.ConditionRulesValid: metav1.ConditionTrue,
+				v1alpha1.ConditionDegraded:   metav1.ConditionFalse,
+			},
+			wantPolicyReason: v1alpha1.ReasonValid,
+		},
+		{
+			name:   "invalid rules",
+			action: v1alpha1.ActionReportOnly,
+			gitleaks: &v1alpha1.GitleaksConfig{
+				Rules: []gitleaks.Rule{{ID: "broken", Regex: "(", Entropy: "1"}},
+			},
+			wantPolicy: map[string]metav1.ConditionStatus{
+				v1alpha1.ConditionReady:      metav1.ConditionTrue,
+				v1alpha1.ConditionRulesValid: metav1.ConditionFalse,
+				v1alpha1.ConditionDegraded:   metav1.ConditionTrue,
+			},
+			wantPolicyReason: v1alpha1.ReasonInvalidRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"k": secretValue},
+			}
+			pol := &v1alpha1.ScanPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol", Generation: 3},
+				Spec: v1alpha1.ScanPolicySpec{
+					Action:         tt.action,
+					MinSeverity:    scanners.SeverityLow,
+					Scanner:        test.DefaultScanner.Name(),
+					HashAlgorithm:  v1alpha1.AlgorithmSHA256,
+					GitleaksConfig: tt.gitleaks,
+				},
+			}
+
+			test.NewFramework(t).Unit(t).
+				WithConfigMap(cm).
+				WithScanPolicy(pol).
+				WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					var sp v1alpha1.ScanPolicy
+					require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKeyFromObject(pol), &sp))
+					require.NotEmpty(t, sp.Status.Message)
+					require.Equal(t, sp.Generation, sp.Status.ObservedGeneration)
+					for typ, status := range tt.wantPolicy {
+						c := meta.FindStatusCondition(sp.Status.Conditions, typ)
+						require.NotNil(t, c, typ)
+						require.Equal(t, status, c.Status, typ)
+						require.Equal(t, sp.Generation, c.ObservedGeneration, typ)
+					}
+					require.Equal(t, tt.wantPolicyReason, meta.FindStatusCondition(sp.Status.Conditions, v1alpha1.ConditionRulesValid).Reason)
+
+					if tt.wantExposed == nil {
+						return
+					}
+					var es v1alpha1.ExposedSecret
+					require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}, &es))
+					for typ, status := range tt.wantExposed {
+						c := meta.FindStatusCondition(es.Status.Conditions, typ)
+						require.NotNil(t, c, typ)
+						require.Equal(t, status, c.Status, typ)
+						require.Equal(t, es.Generation, c.ObservedGeneration, typ)
+					}
+				}).
+				Run()
+		})
+	}
+}
+
+func TestReconcile_Resync(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		resolved    bool
+		wantRequeue time.Duration
+	}{
+		{name: "disabled", config: "resyncInterval: 0s\n"},
+		{name: "resync interval", config: "resyncInterval: 24h\n", wantRequeue: 24 * time.Hour},
+		{
+			name:        "shorter resolved TTL",
+			config:      "resyncInterval: 24h\nresolvedTTL: 1h\n",
+			resolved:    true,
+			wantRequeue: time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"k": secretValue},
+			}
+			cfg, err := config.LoadFS("config.yaml", fstest.MapFS{
+				"config.yaml": &fstest.MapFile{Data: []byte(tt.config)},
+			})
+			require.NoError(t, err)
+
+			u := test.NewFramework(t).Unit(t).WithConfig(cfg).WithConfigMap(cm)
+			if tt.resolved {
+				u = u.WithObjects(&v1alpha1.ExposedSecret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esName("cm", "gone")},
+					Spec:       v1alpha1.ExposedSecretSpec{Action: v1alpha1.ActionReportOnly},
+					Status: v1alpha1.ExposedSecretStatus{
+						ConfigMapReference: v1alpha1.ConfigMapReference{Name: cm.Name},
+						Key:                "gone",
+						Phase:              v1alpha1.PhaseDetected,
+					},
+				})
+			}
+			u.WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(_ *test.Unittest, r ctrl.Result, _ error) {
+					require.InDelta(t, tt.wantRequeue, r.RequeueAfter, float64(time.Second))
+				}).
+				Run()
+		})
+	}
+}
+
+func TestReconcile_Watch(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Labels: map[string]string{"tier": "prod"}}}
+
+	tests := []struct {
+		name        string
+		config      string
+		wantWatched bool
+	}{
+		{name: "everything", config: "", wantWatched: true},
+		{name: "allowed namespace", config: "watch:\n  namespaces: [ns]\n", wantWatched: true},
+		{name: "other namespace", config: "watch:\n  namespaces: [other]\n"},
+		{name: "excluded namespace", config: "watch:\n  excludedNamespaces: [ns]\n"},
+		{name: "matching namespace selector", config: "watch:\n  namespaceSelector: tier=prod\n", wantWatched: true},
+		{name: "namespace selector by name", config: "watch:\n  namespaceSelector: kubernetes.io/metadata.name=ns\n", wantWatched: true},
+		{name: "non-matching namespace selector", config: "watch:\n  namespaceSelector: tier=dev\n"},
+		{name: "matching ConfigMap selector", config: "watch:\n  configMapSelector: app=web\n", wantWatched: true},
+		{name: "non-matching ConfigMap selector", config: "watch:\n  configMapSelector: app!=web\n"},
+		{name: "excluded by field selector", config: "watch:\n  configMapFieldSelector: metadata.name!=cm\n"},
+		{name: "namespaced", config: "watch:\n  namespaces: [ns]\n  namespaced: true\n", wantWatched: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", Labels: map[string]string{"app": "web"}},
+				Data:       map[string]string{"k": secretValue},
+			}
+			cfg, err := config.LoadFS("config.yaml", fstest.MapFS{
+				"config.yaml": &fstest.MapFile{Data: []byte(tt.config)},
+			})
+			require.NoError(t, err)
+
+			test.NewFramework(t).Unit(t).
+				WithConfig(cfg).
+				WithConfigMap(cm).
+				WithObjects(ns).
+				WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					var list v1alpha1.ExposedSecretList
+					require.NoError(t, u.Client.List(u.T.Context(), &list, ctrlclient.InNamespace("ns")))
+					if tt.wantWatched {
+						require.Len(t, list.Items, 1)
+					} else {
+						require.Empty(t, list.Items)
+					}
+				}).
+				Run()
+		})
+	}
+}
+
+func TestLoadConfig_InvalidWatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{name: "invalid namespace selector", config: "watch:\n  namespaceSelector: '!!'\n"},
+		{name: "unsupported field", config: "watch:\n  configMapFieldSelector: data.k=v\n"},
+		{name: "all namespaces excluded", config: "watch:\n  namespaces: [ns]\n  excludedNamespaces: [ns]\n"},
+		{name: "namespaced without namespaces", config: "watch:\n  namespaced: true\n"},
+		{name: "namespaced with namespace selector", config: "watch:\n  namespaces: [ns]\n  namespaced: true\n  namespaceSelector: a=b\n"},
+		{name: "keyed hash algorithm without pepper", config: "defaultScanPolicy: |\n  spec:\n    hashAlgorithm: hmac-sha256\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.LoadFS("config.yaml", fstest.MapFS{
+				"config.yaml": &fstest.MapFile{Data: []byte(tt.config)},
+			})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestReconcile_HMAC(t *testing.T) {
+	pepper := &v1alpha1.Pepper{ID: "2024-01", Key: []byte(strings.Repeat("p", 32))}
+	pepperSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "system", Name: "pepper"},
+		Data:       map[string][]byte{pepper.ID: pepper.Key},
+	}
+
+	tests := []struct {
+		name      string
+		algorithm v1alpha1.HashAlgorithm
+		config    string
+		want      string
+		// wantDegraded is the reason of the policy's Degraded condition.
+		wantDegraded string
+	}{
+		{
+			name:         "hmac-sha256",
+			algorithm:    v1alpha1.AlgorithmHMACSHA256,
+			config:       "pepper:\n  namespace: system\n  secretName: pepper\n  keyID: 2024-01\n",
+			want:         v1alpha1.AlgorithmHMACSHA256.HashWithPepper(secretValue, pepper),
+			wantDegraded: v1alpha1.ReasonValid,
+		},
+		{
+			name:         "hmac-sha512",
+			algorithm:    v1alpha1.AlgorithmHMACSHA512,
+			config:       "pepper:\n  namespace: system\n  secretName: pepper\n  keyID: 2024-01\n",
+			want:         v1alpha1.AlgorithmHMACSHA512.HashWithPepper(secretValue, pepper),
+			wantDegraded: v1alpha1.ReasonValid,
+		},
+		{
+			name:         "unkeyed algorithm ignores the pepper",
+			algorithm:    v1alpha1.AlgorithmSHA256,
+			config:       "pepper:\n  namespace: system\n  secretName: pepper\n  keyID: 2024-01\n",
+			want:         v1alpha1.AlgorithmSHA256.Hash(secretValue),
+			wantDegraded: v1alpha1.ReasonValid,
+		},
+		{
+			name:         "missing pepper masks values",
+			algorithm:    v1alpha1.AlgorithmHMACSHA256,
+			want:         v1alpha1.Mask(secretValue),
+			wantDegraded: v1alpha1.ReasonPepperMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"k": secretValue},
+			}
+			cfg, err := config.LoadFS("config.yaml", fstest.MapFS{
+				"config.yaml": &fstest.MapFile{Data: []byte(tt.config)},
+			})
+			require.NoError(t, err)
+			require.NoError(t, cfg.Pepper.Load(t.Context(), fake.NewClientBuilder().WithObjects(pepperSecret).Build()))
+
+			test.NewFramework(t).Unit(t).
+				WithConfig(cfg).
+				WithConfigMap(cm).
+				WithScanPolicy(&v1alpha1.ScanPolicy{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "policy"},
+					Spec: v1alpha1.ScanPolicySpec{
+						Action:        v1alpha1.ActionReportOnly,
+						MinSeverity:   scanners.SeverityLow,
+						Scanner:       test.DefaultScanner.Name(),
+						HashAlgorithm: tt.algorithm,
+					},
+				}).
+				WithScanner(test.DefaultScanner).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					var es v1alpha1.ExposedSecret
+					require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}, &es))
+					require.Equal(t, tt.want, es.Status.DetectedValue)
+
+					var sp v1alpha1.ScanPolicy
+					require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: "policy"}, &sp))
+					degraded := meta.FindStatusCondition(sp.Status.Conditions, v1alpha1.ConditionDegraded)
+					require.NotNil(t, degraded)
+					require.Equal(t, tt.wantDegraded, degraded.Reason)
+				}).
+				Run()
+		})
+	}
+}
+
+func TestLoadPepper(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string][]byte
+		wantErr bool
+	}{
+		{name: "valid", data: map[string][]byte{"k1": []byte(strings.Repeat("p", 32))}},
+		{name: "missing key", data: map[string][]byte{"k0": []byte(strings.Repeat("p", 32))}, wantErr: true},
+		{name: "too short", data: map[string][]byte{"k1": []byte("short")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.LoadFS("config.yaml", fstest.MapFS{
+				"config.yaml": &fstest.MapFile{Data: []byte("pepper:\n  namespace: system\n  secretName: pepper\n  keyID: k1\n")},
+			})
+			require.NoError(t, err)
+
+			cl := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "system", Name: "pepper"},
+				Data:       tt.data,
+			}).Build()
+			err = cfg.Pepper.Load(t.Context(), cl)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, cfg.Pepper.Get())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, &v1alpha1.Pepper{ID: "k1", Key: tt.data["k1"]}, cfg.Pepper.Get())
+		})
+	}
+}
+
+func TestReconcile_SkipUnchanged(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(t *testing.T, u *test.Unittest)
+		wantSkip bool
+	}{
+		{name: "unchanged", change: func(*testing.T, *test.Unittest) {}, wantSkip: true},
+		{
+			name: "labels changed",
+			change: func(t *testing.T, u *test.Unittest) {
+				updateConfigMap(t, u, func(cm *corev1.ConfigMap) { cm.Labels = map[string]string{"team": "a"} })
+			},
+		},
+		{
+			name: "unrelated annotations changed",
+			change: func(t *testing.T, u *test.Unittest) {
+				updateConfigMap(t, u, func(cm *corev1.ConfigMap) { cm.Annotations = map[string]string{"note": "a"} })
+			},
+			wantSkip: true,
+		},
+		{
+			name: "data changed",
+			change: func(t *testing.T, u *test.Unittest) {
+				updateConfigMap(t, u, func(cm *corev1.ConfigMap) { cm.Data["other"] = secretValue })
+			},
+		},
+		{
+			name: "policy changed",
+			change: func(t *testing.T, u *test.Unittest) {
+				require.NoError(t, u.Client.Create(u.T.Context(), &v1alpha1.ScanPolicy{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol"},
+					Spec: v1alpha1.ScanPolicySpec{
+						Action:      v1alpha1.ActionIgnore,
+						MinSeverity: scanners.SeverityLow,
+						Scanner:     test.DefaultScanner.Name(),
+					},
+				}))
+			},
+		},
+		{
+			name: "exposed secret changed",
+			change: func(t *testing.T, u *test.Unittest) {
+				var es v1alpha1.ExposedSecret
+				key := ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}
+				require.NoError(t, u.Client.Get(u.T.Context(), key, &es))
+				es.Spec.Action = v1alpha1.ActionIgnore
+				es.Generation++
+				require.NoError(t, u.Client.Update(u.T.Context(), &es))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", UID: "cm-uid"},
+				Data:       map[string]string{"k": secretValue},
+			}
+
+			test.NewFramework(t).Unit(t).
+				WithConfigMap(cm).
+				WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					tt.change(t, u)
+
+					skipped := testutil.ToFloat64(controllers.ReconcilesSkipped.WithLabelValues("ns"))
+					ctx := logr.NewContextWithSlogLogger(u.T.Context(), slog.Default())
+					_, err := u.Reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: ctrlclient.ObjectKeyFromObject(cm)})
+					require.NoError(t, err)
+
+					want := skipped
+					if tt.wantSkip {
+						want++
+					}
+					require.InDelta(t, want, testutil.ToFloat64(controllers.ReconcilesSkipped.WithLabelValues("ns")), 0)
+				}).
+				Run()
+		})
+	}
+}
+
+func TestReconcile_SkipAnnotationRemoved(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "cm",
+			UID:         "cm-uid",
+			Annotations: map[string]string{v1alpha1.AnnotationSkip: "true"},
+		},
+		Data: map[string]string{"k": secretValue},
+	}
+
+	test.NewFramework(t).Unit(t).
+		WithConfigMap(cm).
+		WithScanner(test.DefaultScanner).
+		WantError(false).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+			key := ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}
+			err := u.Client.Get(u.T.Context(), key, &v1alpha1.ExposedSecret{})
+			require.True(t, apierrors.IsNotFound(err), "expected no ExposedSecret, got %v", err)
+
+			updateConfigMap(t, u, func(cm *corev1.ConfigMap) { delete(cm.Annotations, v1alpha1.AnnotationSkip) })
+			ctx := logr.NewContextWithSlogLogger(u.T.Context(), slog.Default())
+			_, err = u.Reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: ctrlclient.ObjectKeyFromObject(cm)})
+			require.NoError(t, err)
+			require.NoError(t, u.Client.Get(u.T.Context(), key, &v1alpha1.ExposedSecret{}))
+		}).
+		Run()
+}
+
+// updateConfigMap applies the mutation to the ConfigMap "ns/cm" in the cluster.
+func updateConfigMap(t *testing.T, u *test.Unittest, mutate func(*corev1.ConfigMap)) {
+	t.Helper()
+	var cm corev1.ConfigMap
+	require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: "cm"}, &cm))
+	mutate(&cm)
+	require.NoError(t, u.Client.Update(u.T.Context(), &cm))
+}
+
+func TestReconcile_Plaintext(t *testing.T) {
+	plaintext := base64.StdEncoding.EncodeToString([]byte(secretValue))
+	stale := &v1alpha1.ExposedSecret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esName("cm", "gone")},
+		Spec:       v1alpha1.ExposedSecretSpec{Action: v1alpha1.ActionReportOnly},
+		Status: v1alpha1.ExposedSecretStatus{
+			ConfigMapReference: v1alpha1.ConfigMapReference{Name: "cm"},
+			Key:                "gone",
+			DetectedValue:      plaintext,
+			Phase:              v1alpha1.PhaseDetected,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		algorithm v1alpha1.HashAlgorithm
+		config    string
+		objects   []ctrlclient.Object
+		want      map[string]string
+	}{
+		{
+			name:      "masked",
+			algorithm: v1alpha1.AlgorithmMasked,
+			want:      map[string]string{"k": v1alpha1.Mask(secretValue)},
+		},
+		{
+			name:      "plaintext is masked unless allowed",
+			algorithm: v1alpha1.AlgorithmNone,
+			want:      map[string]string{"k": v1alpha1.Mask(secretValue)},
+		},
+		{
+			name:      "plaintext allowed",
+			algorithm: v1alpha1.AlgorithmNone,
+			config:    "allowPlaintext: true\n",
+			want:      map[string]string{"k": plaintext},
+		},
+		{
+			name:      "stored plaintext is re-hashed",
+			algorithm: v1alpha1.AlgorithmSHA256,
+			objects:   []ctrlclient.Object{stale},
+			want: map[string]string{
+				"k":    v1alpha1.AlgorithmSHA256.Hash(secretValue),
+				"gone": v1alpha1.AlgorithmSHA256.Hash(secretValue),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.LoadFS("config.yaml", fstest.MapFS{
+				"config.yaml": &fstest.MapFile{Data: []byte(tt.config)},
+			})
+			require.NoError(t, err)
+
+			test.NewFramework(t).Unit(t).
+				WithConfig(cfg).
+				WithConfigMap(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+					Data:       map[string]string{"k": secretValue},
+				}).
+				WithObjects(tt.objects...).
+				WithScanPolicy(&v1alpha1.ScanPolicy{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "policy"},
+					Spec: v1alpha1.ScanPolicySpec{
+						Action:        v1alpha1.ActionReportOnly,
+						MinSeverity:   scanners.SeverityLow,
+						Scanner:       test.DefaultScanner.Name(),
+						HashAlgorithm: tt.algorithm,
+					},
+				}).
+				WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					for key, want := range tt.want {
+						var es v1alpha1.ExposedSecret
+						require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", key)}, &es))
+						require.Equal(t, want, es.Status.DetectedValue, key)
+					}
+				}).
+				Run()
+		})
+	}
+}
+
+func TestReconcile_Fingerprint(t *testing.T) {
+	pepper := &v1alpha1.Pepper{ID: "2024-01", Key: []byte(strings.Repeat("p", 32))}
+	fingerprint := v1alpha1.NewFingerprint(secretValue, pepper)
+	cfg, err := config.LoadFS("config.yaml", fstest.MapFS{
+		"config.yaml": &fstest.MapFile{Data: []byte("pepper:\n  namespace: system\n  secretName: pepper\n  keyID: 2024-01\n")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, cfg.Pepper.Load(t.Context(), fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "system", Name: "pepper"},
+		Data:       map[string][]byte{pepper.ID: pepper.Key},
+	}).Build()))
+
+	// The same value copied into another namespace, where it was already remediated.
+	elsewhere := &v1alpha1.ExposedSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "other",
+			Name:      "copy-token",
+			Labels:    map[string]string{v1alpha1.LabelFingerprint: fingerprint},
+		},
+		Status: v1alpha1.ExposedSecretStatus{
+			ConfigMapReference: v1alpha1.ConfigMapReference{Name: "copy"},
+			Key:                "token",
+			Fingerprint:        fingerprint,
+			Phase:              v1alpha1.PhaseRemediated,
+		},
+	}
+
+	test.NewFramework(t).Unit(t).
+		WithConfig(cfg).
+		WithConfigMap(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+			// The same value with different surrounding content, so only the secret itself is fingerprinted.
+			Data: map[string]string{"a": "token=" + secretValue, "b": "postgres://app:" + secretValue + "@db:5432/app"},
+		}).
+		WithObjects(elsewhere).
+		WithScanPolicy(&v1alpha1.ScanPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "policy"},
+			Spec: v1alpha1.ScanPolicySpec{
+				Action:        v1alpha1.ActionReportOnly,
+				MinSeverity:   scanners.SeverityLow,
+				Scanner:       test.DefaultScanner.Name(),
+				HashAlgorithm: v1alpha1.AlgorithmSHA256,
+			},
+		}).
+		WithScanner(test.DefaultScanner).
+		WantError(false).
+		WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+			ctx := logr.NewContextWithSlogLogger(u.T.Context(), slog.Default())
+			for _, key := range []string{"a", "b"} {
+				var es v1alpha1.ExposedSecret
+				require.NoError(t, u.Client.Get(ctx, ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", key)}, &es))
+				require.Equal(t, fingerprint, es.Status.Fingerprint, key)
+				require.Equal(t, fingerprint, es.Labels[v1alpha1.LabelFingerprint], key)
+			}
+
+			r := controllers.NewSecretFingerprintReconciler(u.Client, cfg)
+			req := ctrl.Request{NamespacedName: ctrlclient.ObjectKey{Name: fingerprint}}
+			_, err := r.Reconcile(ctx, req)
+			require.NoError(t, err)
+
+			var fp v1alpha1.SecretFingerprint
+			require.NoError(t, u.Client.Get(ctx, req.NamespacedName, &fp))
+			require.Equal(t, pepper.ID, fp.Status.KeyID)
+			require.Equal(t, 3, fp.Status.LocationCount)
+			require.Equal(t, 2, fp.Status.ExposedCount)
+			require.Equal(t, []v1alpha1.SecretLocation{
+				{Namespace: "ns", ConfigMap: "cm", Location: "a", ExposedSecret: esName("cm", "a"), Phase: v1alpha1.PhaseDetected},
+				{Namespace: "ns", ConfigMap: "cm", Location: "b", ExposedSecret: esName("cm", "b"), Phase: v1alpha1.PhaseDetected},
+				{Namespace: "other", ConfigMap: "copy", Location: "token", ExposedSecret: "copy-token", Phase: v1alpha1.PhaseRemediated},
+			}, fp.Status.Locations)
+
+			// Once all copies are gone, the aggregate is deleted.
+			require.NoError(t, u.Client.DeleteAllOf(ctx, &v1alpha1.ExposedSecret{}, ctrlclient.InNamespace("ns")))
+			require.NoError(t, u.Client.Delete(ctx, elsewhere))
+			_, err = r.Reconcile(ctx, req)
+			require.NoError(t, err)
+			require.True(t, apierrors.IsNotFound(u.Client.Get(ctx, req.NamespacedName, &fp)))
+		}).
+		Run()
+}
+
+func TestReconcile_Exception(t *testing.T) {
+	exception := func(name string, expiresIn time.Duration, spec v1alpha1.SecretExceptionSpec) *v1alpha1.SecretException {
+		spec.Reason = "Test fixture"
+		spec.Owner = "team-a"
+		spec.ExpiresAt = metav1.NewTime(time.Now().Add(expiresIn))
+		return &v1alpha1.SecretException{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}, Spec: spec}
+	}
+	// excepted is an ExposedSecret that was excepted during a previous reconciliation.
+	excepted := &v1alpha1.ExposedSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        esName("cm", "k"),
+			Annotations: map[string]string{v1alpha1.AnnotationException: "fixture"},
+		},
+		Spec: v1alpha1.ExposedSecretSpec{Action: v1alpha1.ActionIgnore},
+		Status: v1alpha1.ExposedSecretStatus{
+			ConfigMapReference: v1alpha1.ConfigMapReference{Name: "cm"},
+			Key:                "k",
+			Phase:              v1alpha1.PhaseIgnored,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		objects       []ctrlclient.Object
+		wantPhase     v1alpha1.Phase
+		wantException string
+		wantRequeue   time.Duration
+	}{
+		{
+			name:          "excepted by key",
+			objects:       []ctrlclient.Object{exception("fixture", time.Hour, v1alpha1.SecretExceptionSpec{Keys: []string{"k*"}})},
+			wantPhase:     v1alpha1.PhaseIgnored,
+			wantException: "fixture",
+			wantRequeue:   time.Hour,
+		},
+		{
+			name: "excepted by rule and ConfigMap",
+			objects: []ctrlclient.Object{exception("fixture", time.Hour, v1alpha1.SecretExceptionSpec{
+				ConfigMapNames:    []string{"cm"},
+				ConfigMapSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "test"}},
+				RuleIDs:           []string{test.DefaultRuleID},
+			})},
+			wantPhase:     v1alpha1.PhaseIgnored,
+			wantException: "fixture",
+			wantRequeue:   time.Hour,
+		},
+		{
+			name:      "not matching",
+			objects:   []ctrlclient.Object{exception("fixture", time.Hour, v1alpha1.SecretExceptionSpec{RuleIDs: []string{"other-rule"}})},
+			wantPhase: v1alpha1.PhaseDetected,
+		},
+		{
+			name: "expired exception re-opens the finding",
+			objects: []ctrlclient.Object{
+				exception("fixture", -time.Minute, v1alpha1.SecretExceptionSpec{Keys: []string{"k"}}),
+				excepted,
+			},
+			wantPhase: v1alpha1.PhaseDetected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test.NewFramework(t).Unit(t).
+				WithConfigMap(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", Labels: map[string]string{"env": "test"}},
+					Data:       map[string]string{"k": secretValue},
+				}).
+				WithObjects(tt.objects...).
+				WithScanPolicy(&v1alpha1.ScanPolicy{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "policy"},
+					Spec: v1alpha1.ScanPolicySpec{
+						Action:        v1alpha1.ActionReportOnly,
+						MinSeverity:   scanners.SeverityLow,
+						Scanner:       test.DefaultScanner.Name(),
+						HashAlgorithm: v1alpha1.AlgorithmSHA256,
+					},
+				}).
+				WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(u *test.Unittest, r ctrl.Result, _ error) {
+					var es v1alpha1.ExposedSecret
+					require.NoError(t, u.Client.Get(u.T.Context(), ctrlclient.ObjectKey{Namespace: "ns", Name: esName("cm", "k")}, &es))
+					require.Equal(t, tt.wantPhase, es.Status.Phase)
+					require.Equal(t, tt.wantException, es.Annotations[v1alpha1.AnnotationException])
+					if tt.wantException != "" {
+						require.Equal(t, v1alpha1.ActionIgnore, es.Spec.Action)
+						require.Contains(t, es.Status.Message, "Test fixture")
+					} else {
+						require.Equal(t, v1alpha1.ActionReportOnly, es.Spec.Action)
+					}
+					require.InDelta(t, tt.wantRequeue, r.RequeueAfter, float64(time.Minute))
+				}).
+				Run()
+		})
+	}
+}
+
+func TestReconcile_Exclusions(t *testing.T) {
+	tests := []struct {
+		name       string
+		cm         *corev1.ConfigMap
+		spec       v1alpha1.ScanPolicySpec
+		wantKeys   []string
+		wantReason string
+	}{
+		{
+			name:     "not excluded",
+			cm:       &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"k": secretValue}},
+			wantKeys: []string{"k"},
+		},
+		{
+			name: "keys excluded by glob, regex and name",
+			cm: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{
+				"ca.crt":       secretValue,
+				"public_token": secretValue,
+				"fixture":      secretValue,
+				"k":            secretValue,
+			}},
+			spec:       v1alpha1.ScanPolicySpec{ExcludedKeys: []string{"*.crt", "/^public_.+$/", "fixture", "/k.+/"}},
+			wantKeys:   []string{"k"},
+			wantReason: "excluded_key",
+		},
+		{
+			name: "opted out by annotation",
+			cm: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cm", Annotations: map[string]string{v1alpha1.AnnotationSkip: "true"}},
+				Data:       map[string]string{"k": secretValue},
+			},
+			wantReason: "annotation",
+		},
+		{
+			name:       "ConfigMap excluded by name",
+			cm:         &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt"}, Data: map[string]string{"k": secretValue}},
+			spec:       v1alpha1.ScanPolicySpec{ExcludedConfigMaps: []string{"kube-*.crt"}},
+			wantReason: "configmap_name",
+		},
+		{
+			name: "ConfigMap excluded by labels",
+			cm: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cm", Labels: map[string]string{"grafana_dashboard": "1"}},
+				Data:       map[string]string{"k": secretValue},
+			},
+			spec: v1alpha1.ScanPolicySpec{ExcludedConfigMapSelectors: []metav1.LabelSelector{
+				{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "grafana_dashboard", Operator: metav1.LabelSelectorOpExists}}},
+			}},
+			wantReason: "configmap_selector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cm.Namespace = "exclusions"
+			spec := tt.spec
+			spec.Action = v1alpha1.ActionReportOnly
+			spec.MinSeverity = scanners.SeverityLow
+			spec.Scanner = test.DefaultScanner.Name()
+			spec.HashAlgorithm = v1alpha1.AlgorithmSHA256
+
+			var skipped float64
+			if tt.wantReason != "" {
+				skipped = testutil.ToFloat64(controllers.ScansSkipped.WithLabelValues("exclusions", tt.wantReason))
+			}
+			test.NewFramework(t).Unit(t).
+				WithConfigMap(tt.cm).
+				WithScanPolicy(&v1alpha1.ScanPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "exclusions", Name: "policy"}, Spec: spec}).
+				WithScanner(test.DefaultScanner).
+				WantError(false).
+				WithAssertion(func(u *test.Unittest, _ ctrl.Result, _ error) {
+					var list v1alpha1.ExposedSecretList
+					require.NoError(t, u.Client.List(u.T.Context(), &list))
+					var keys []string
+					for i := range list.Items {
+						keys = append(keys, list.Items[i].Status.Key)
+					}
+					require.ElementsMatch(t, tt.wantKeys, keys)
+
+					if tt.wantReason != "" {
+						require.Greater(t, testutil.ToFloat64(controllers.ScansSkipped.WithLabelValues("exclusions", tt.wantReason)), skipped)
+					}
+				}).
+				Run()
+		})
+	}
+}
@@ -5,8 +5,10 @@ import (
 	stderrors "errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"reflect"
 	"slices"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
@@ -15,6 +17,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -24,29 +28,63 @@ type recCtx struct {
 	ctx context.Context
 	// cl is the Kubernetes client used to interact with the cluster.
 	cl client.Client
+	// recorder emits Events regarding the reconciled resources, see [recCtx.event].
+	recorder events.EventRecorder
 	// scanner is the secret scanner used to detect secrets in the [corev1.ConfigMap].
 	scanner scanners.Scanner
 	// policy is the policy policy derived from the [v1alpha1.ScanPolicy].
 	policy *v1alpha1.ScanPolicy
+	// policySource identifies where the policy came from, see [v1alpha1.PolicySource].
+	policySource string
+	// exclusions are the compiled exclusions of the policy.
+	exclusions *v1alpha1.Exclusions
 	// configMap is the [corev1.ConfigMap] being reconciled.
 	configMap *corev1.ConfigMap
+	// admission is true if the [corev1.ConfigMap] is remediated on admission, see [v1alpha1.AdmissionRemediate].
+	// Secrets that would only be reported are then remediated as well and the ConfigMap is mutated regardless of the policy.
+	admission bool
+	// inPlace is true if the [corev1.ConfigMap] isn't stored yet, i.e. on admission.
+	// It is then mutated in place instead of being updated in the cluster.
+	inPlace bool
+	// resolvedTTL is the time after which resolved [v1alpha1.ExposedSecret] resources are deleted.
+	// Resolved ExposedSecrets are kept forever if it is zero.
+	resolvedTTL time.Duration
+	// pepper is the key of the keyed hashing algorithms, see [v1alpha1.HashAlgorithm.HashWithPepper].
+	pepper *v1alpha1.Pepper
+	// allowPlaintext allows [v1alpha1.AlgorithmNone], otherwise such values are masked.
+	allowPlaintext bool
+	// requeueAfter is the time after which the ConfigMap needs to be reconciled again, e.g. to
+	// delete resolved ExposedSecrets once their TTL expired. Zero means no requeue is needed.
+	requeueAfter time.Duration
+	// exceptions are the [v1alpha1.SecretException] resources in the ConfigMap's namespace, see [recCtx.exception].
+	exceptions []v1alpha1.SecretException
+	// rewired holds the workloads rewired to the Secret of a ConfigMap key, see [recCtx.rewireWorkloads].
+	rewired map[string][]v1alpha1.WorkloadReference
 
 	// log is the logger used for logging messages during reconciliation.
 	log *slog.Logger
 }
 
 // newRecCtx creates a new [recCtx] for a given [v1alpha1.ScanPolicy] and [corev1.ConfigMap].
-func newRecCtx(c client.Client, policy *v1alpha1.ScanPolicy, cm *corev1.ConfigMap) *recCtx {
+// Events are discarded if the recorder is nil.
+func newRecCtx(c client.Client, rec events.EventRecorder, policy *appliedPolicy, cm *corev1.ConfigMap) *recCtx {
+	if rec == nil {
+		rec = noopRecorder
+	}
 	rc := &recCtx{
-		cl:        c,
-		policy:    policy,
-		configMap: cm,
+		cl:           c,
+		recorder:     rec,
+		policy:       policy.ScanPolicy,
+		policySource: policy.source,
+		configMap:    cm,
 	}
 	return rc
 }
 
 // run executes the reconciliation for the ConfigMap: it scans for secret-like keys,
 // filters excluded keys, and processes each remaining key according to policy.
+// Reported secrets that are no longer found in the ConfigMap are resolved
+// and the created resources are linked to the ConfigMap with owner references.
 func (rc *recCtx) run(ctx context.Context) error {
 	err := rc.initCtx(ctx)
 	if err != nil {
@@ -54,26 +92,70 @@ func (rc *recCtx) run(ctx context.Context) error {
 		rc.log.ErrorContext(ctx, "Failed to initialize reconciliation context", "error", err)
 		return fmt.Errorf("failed to initialize reconciliation context: %w", err)
 	}
+	if rc.skipped() {
+		return nil
+	}
+
+	if err = rc.migrateNames(); err != nil {
+		ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageMigrate).Inc()
+		rc.log.ErrorContext(ctx, "Failed to migrate ExposedSecret names", "error", err)
+		return fmt.Errorf("failed to migrate ExposedSecret names: %w", err)
+	}
+
+	if err = rc.rehashFindings(); err != nil {
+		ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageMigrate).Inc()
+		rc.log.ErrorContext(ctx, "Failed to re-hash ExposedSecrets", "error", err)
+		return fmt.Errorf("failed to re-hash ExposedSecrets: %w", err)
+	}
 
-	keys := rc.findSecretKeys()
-	KeysScanned.WithLabelValues(rc.configMap.Namespace).Observe(float64(len(rc.configMap.Data)))
-	if len(keys) == 0 {
+	if err = rc.revertRemediations(); err != nil {
+		ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageRevert).Inc()
+		rc.log.ErrorContext(ctx, "Failed to revert remediations", "error", err)
+		return fmt.Errorf("failed to revert remediations: %w", err)
+	}
+
+	candidates, err := rc.findCandidates()
+	if err != nil {
+		ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageScan).Inc()
+		rc.log.ErrorContext(ctx, "Failed to scan ConfigMap", "error", err)
+		return fmt.Errorf("failed to scan ConfigMap: %w", err)
+	}
+	KeysScanned.WithLabelValues(rc.configMap.Namespace).Observe(float64(len(rc.configMap.Data) + len(rc.configMap.BinaryData)))
+	if len(candidates) == 0 {
 		rc.log.DebugContext(ctx, "No secret-like data keys found")
-		return nil
 	}
 
-	for _, key := range keys {
-		if slices.Contains(rc.policy.Spec.ExcludedKeys, key) {
-			rc.log.DebugContext(ctx, "Key excluded from scanning", "key", key)
+	found := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		found[c.name(rc.configMap)] = true
+		if rc.excluded(c) {
 			continue
 		}
 
-		if perr := rc.process(key); perr != nil {
+		if perr := rc.process(c); perr != nil {
 			ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageProcessKey).Inc()
-			rc.log.ErrorContext(ctx, "Failed to process key", "key", key, "error", perr)
+			rc.log.ErrorContext(ctx, "Failed to process key", "key", c.key, "innerPath", c.innerPath, "path", c.path, "error", perr)
 			return perr
 		}
-		rc.log.DebugContext(ctx, "Processed key", "key", key)
+		rc.log.DebugContext(ctx, "Processed key", "key", c.key, "innerPath", c.innerPath, "path", c.path)
+	}
+
+	if err = rc.unmarkRemediation(); err != nil {
+		ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageRemediate).Inc()
+		rc.log.ErrorContext(ctx, "Failed to remove remediation mark", "error", err)
+		return fmt.Errorf("failed to remove remediation mark: %w", err)
+	}
+
+	if err = rc.resolveFindings(found); err != nil {
+		ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageResolve).Inc()
+		rc.log.ErrorContext(ctx, "Failed to resolve findings", "error", err)
+		return fmt.Errorf("failed to resolve findings: %w", err)
+	}
+
+	if err = rc.reconcileOwnership(); err != nil {
+		ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageOwnership).Inc()
+		rc.log.ErrorContext(ctx, "Failed to reconcile ownership", "error", err)
+		return fmt.Errorf("failed to reconcile ownership: %w", err)
 	}
 	return nil
 }
@@ -81,6 +163,24 @@ func (rc *recCtx) run(ctx context.Context) error {
 func (rc *recCtx) initCtx(ctx context.Context) error {
 	rc.ctx = ctx
 	rc.log = logr.FromContextAsSlogLogger(ctx).With("ConfigMap", rc.configMap.Name)
+	if rc.policy.Spec.HashAlgorithm == v1alpha1.AlgorithmNone && !rc.allowPlaintext {
+		rc.log.WarnContext(ctx, "Plaintext values are not allowed by the operator's configuration, masking them instead",
+			"policy", rc.policySource)
+		rc.policy.Spec.HashAlgorithm = v1alpha1.AlgorithmMasked
+	}
+	if rc.policy.Spec.HashAlgorithm.Keyed() && rc.pepper == nil {
+		rc.log.WarnContext(ctx, "Hash algorithm requires a pepper in the operator's configuration, masking values instead",
+			"policy", rc.policySource, "algorithm", rc.policy.Spec.HashAlgorithm)
+		rc.policy.Spec.HashAlgorithm = v1alpha1.AlgorithmMasked
+	}
+	// ConfigMaps marked on admission are only remediated if the policy still remediates on admission,
+	// since anyone allowed to write the ConfigMap can set the annotation. Otherwise, it is removed, see [recCtx.unmarkRemediation].
+	rc.admission = rc.configMap.Annotations[v1alpha1.AnnotationRemediate] == "true" &&
+		rc.policy.Spec.AdmissionMode == v1alpha1.AdmissionRemediate
+	rc.exclusions = rc.policy.Spec.Exclusions()
+	if err := rc.loadExceptions(); err != nil {
+		return err
+	}
 	scanner, err := factory.Get(ctx, rc.policy.Spec.Scanner, scanners.Config(rc.policy.Spec.GitleaksConfig))
 	if err != nil {
 		return fmt.Errorf("failed to get scanner: %w", err)
@@ -90,25 +190,59 @@ func (rc *recCtx) initCtx(ctx context.Context) error {
 	return nil
 }
 
-// process handles a single ConfigMap key: it builds an ExposedSecret, creates it if missing,
-// resolves the effective action, and dispatches to the appropriate handler.
-func (rc *recCtx) process(key string) error {
-	value := rc.configMap.Data[key]
-	sev := rc.scanner.DetectSeverity(value)
+// requeue requeues the ConfigMap after the given time, unless it is already requeued earlier.
+func (rc *recCtx) requeue(after time.Duration) {
+	if rc.requeueAfter == 0 || after < rc.requeueAfter {
+		rc.requeueAfter = after
+	}
+}
 
-	existing := v1alpha1.ExposedSecret{Spec: v1alpha1.ExposedSecretSpec{Action: v1alpha1.DefaultAction}}
-	err := rc.cl.Get(rc.ctx, client.ObjectKey{Namespace: rc.configMap.Namespace, Name: v1alpha1.NewExposedSecretName(rc.configMap, key)}, &existing)
-	if err != nil && !errors.IsNotFound(err) {
-		rc.log.ErrorContext(rc.ctx, "Failed to get ExposedSecret", "error", err)
-		return fmt.Errorf("failed to get ExposedSecret: %w", err)
+// excluded reports whether the candidate's key is excluded by the policy.
+func (rc *recCtx) excluded(c candidate) bool {
+	pattern := rc.exclusions.KeyPattern(c.key)
+	if pattern == "" {
+		return false
 	}
+	rc.log.DebugContext(rc.ctx, "Key excluded from scanning", "key", c.key, "reason", skipExcludedKey, "pattern", pattern)
+	ScansSkipped.WithLabelValues(rc.configMap.Namespace, skipExcludedKey).Inc()
+	return true
+}
 
-	builder := v1alpha1.NewExposedSecretBuilder(rc.configMap, key).
-		WithPolicy(rc.policy).
-		WithExisting(&existing).
-		WithSeverity(sev)
+// skipped reports whether the whole ConfigMap is skipped, because it opted out
+// with [v1alpha1.AnnotationSkip] or is excluded by the policy.
+func (rc *recCtx) skipped() bool {
+	reason, match := rc.skipReason()
+	if reason == "" {
+		return false
+	}
+	rc.log.DebugContext(rc.ctx, "ConfigMap excluded from scanning", "reason", reason, "match", match)
+	ScansSkipped.WithLabelValues(rc.configMap.Namespace, reason).Inc()
+	return true
+}
 
-	res := rc.computeResolvedAction(builder, sev)
+// skipReason returns the reason the ConfigMap is skipped and the annotation, pattern or selector causing it.
+// The reason is empty if the ConfigMap is scanned.
+func (rc *recCtx) skipReason() (reason, match string) {
+	cm := rc.configMap
+	if cm.Annotations[v1alpha1.AnnotationSkip] == "true" {
+		return skipAnnotation, v1alpha1.AnnotationSkip
+	}
+	if pattern := rc.exclusions.ConfigMapPattern(cm.Name); pattern != "" {
+		return skipConfigMapName, pattern
+	}
+	if selector := rc.exclusions.ConfigMapSelector(cm.Labels); selector != "" {
+		return skipConfigMapSelector, selector
+	}
+	return "", ""
+}
+
+// process handles a single candidate: it builds an ExposedSecret, creates it if missing,
+// resolves the effective action, and dispatches to the appropriate handler.
+func (rc *recCtx) process(c candidate) error {
+	builder, res, err := rc.resolve(c)
+	if err != nil {
+		return err
+	}
 	rc.log.DebugContext(rc.ctx, "Resolved action",
 		"action", res.Action, "severity", res.FinalSeverity,
 		"phase", res.FinalPhase, "message", res.Message)
@@ -120,63 +254,131 @@ func (rc *recCtx) process(key string) error {
 		WithPhase(res.FinalPhase).
 		WithSeverity(res.FinalSeverity)
 
-	if err = rc.doSideEffects(res, builder, key); err != nil {
+	if err = rc.doSideEffects(res, builder, c); err != nil {
 		ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageSideEffect).Inc()
 		rc.log.ErrorContext(rc.ctx, "Failed to do side effects", "error", err)
 		return fmt.Errorf("failed to do side effects: %w", err)
 	}
 
 	es := builder.Build()
+	if err = rc.controlExposedSecret(es); err != nil {
+		return err
+	}
 	// Save the status before createOrUpdate, because the API server response
 	// from Update() overwrites es in-place and strips the status subresource.
 	savedStatus := es.Status
-	if err = rc.createOrUpdate(es); err != nil {
+	if err = rc.createOrUpdate(es, v1alpha1.AnnotationException, v1alpha1.LabelFingerprint); err != nil {
 		rc.log.ErrorContext(rc.ctx, "Failed to create or update ExposedSecret", "error", err)
 		return fmt.Errorf("failed to create or update ExposedSecret: %w", err)
 	}
 
 	// Restore the status so Status().Update() sends the correct populated status.
 	es.Status = savedStatus
+	es.Status.SetConditions(es.Generation)
 	if err = rc.cl.Status().Update(rc.ctx, es); err != nil {
 		rc.log.ErrorContext(rc.ctx, "Failed to update ExposedSecret status", "error", err)
 		return fmt.Errorf("failed to update ExposedSecret status: %w", err)
 	}
 	rc.log.DebugContext(rc.ctx, "Created or updated ExposedSecret")
+	rc.findingEvents(es, builder.ExistingPhase())
+	rc.phaseChanged(es, builder.ExistingPhase())
 	return nil
 }
 
 // doSideEffects performs any side effects required by the resolved action.
 // This function mutates the provided ExposedSecretBuilder in place to reflect
 // the changes caused by the side effects (e.g., remediation).
-func (rc *recCtx) doSideEffects(res ResolvedAction, builder *v1alpha1.ExposedSecretBuilder, key string) error {
+func (rc *recCtx) doSideEffects(res ResolvedAction, builder *v1alpha1.ExposedSecretBuilder, c candidate) error {
 	if res.Action == v1alpha1.ActionAutoRemediate {
-		secret, rErr := rc.doRemediation(builder, key)
+		secret, rErr := rc.doRemediation(c)
 		if rErr != nil {
 			ReconcileErrors.WithLabelValues(rc.configMap.Namespace, stageRemediate).Inc()
 			rc.log.ErrorContext(rc.ctx, "Failed to do remediation", "error", rErr)
 			return fmt.Errorf("failed to do remediation: %w", rErr)
 		}
-		builder.WithRemediated(secret)
+		builder.WithRemediated(secret).WithRewiredWorkloads(rc.rewired[c.key]...)
 	}
 
 	return nil
 }
 
-func (rc *recCtx) computeResolvedAction(b *v1alpha1.ExposedSecretBuilder, sev scanners.Severity) ResolvedAction {
+// resolve builds the ExposedSecret reporting the candidate and resolves the action to take.
+// An action set by the user on an existing ExposedSecret overrides the policy.
+func (rc *recCtx) resolve(c candidate) (*v1alpha1.ExposedSecretBuilder, ResolvedAction, error) {
+	sev := c.severity()
+
+	existing := v1alpha1.ExposedSecret{Spec: v1alpha1.ExposedSecretSpec{Action: v1alpha1.DefaultAction}}
+	err := rc.cl.Get(rc.ctx, client.ObjectKey{Namespace: rc.configMap.Namespace, Name: c.name(rc.configMap)}, &existing)
+	if err != nil && !errors.IsNotFound(err) {
+		rc.log.ErrorContext(rc.ctx, "Failed to get ExposedSecret", "error", err)
+		return nil, ResolvedAction{}, fmt.Errorf("failed to get ExposedSecret: %w", err)
+	}
+
+	builder := v1alpha1.NewExposedSecretBuilder(rc.configMap, c.key)
+	if c.binary {
+		builder = builder.WithBinaryData(c.innerPath, []byte(c.value))
+	}
+	if c.path != "" {
+		builder = builder.WithPath(c.path, c.value)
+	}
+	builder = builder.
+		WithPolicy(rc.policy).
+		WithPolicySource(rc.policySource).
+		WithPepper(rc.pepper).
+		WithExisting(&existing).
+		WithFindings(c.findings).
+		WithSeverity(sev)
+
+	return builder, rc.computeResolvedAction(builder, c, sev), nil
+}
+
+func (rc *recCtx) computeResolvedAction(b *v1alpha1.ExposedSecretBuilder, c candidate, sev scanners.Severity) ResolvedAction {
+	exception := rc.exception(b, c)
+	if exception != nil {
+		b.WithException(exception.Name)
+	} else if b.ExistingException() != "" {
+		rc.log.InfoContext(rc.ctx, "SecretException no longer applies, re-opening finding",
+			"SecretException", b.ExistingException(), "location", c.location())
+	}
+
 	res := ActionResolver{
 		OverrideAction: b.ExistingAction(),
 		HasOverride:    b.Override(),
 		DefaultPolicy:  rc.policy.Spec.Action,
 		Severity:       sev,
 		MinSeverity:    rc.policy.Spec.MinSeverity,
+		Exception:      exception,
+	}.Resolve()
+
+	if rc.admission && !b.Override() && res.Action == v1alpha1.ActionReportOnly {
+		// Secrets must not stay in ConfigMaps marked for remediation on admission,
+		// so secrets that would only be reported are remediated as well.
+		res.Action = v1alpha1.ActionAutoRemediate
+		res.Message = "auto-remediation on admission"
 	}
-	return res.Resolve()
+	return res
 }
 
-func (rc *recCtx) doRemediation(b *v1alpha1.ExposedSecretBuilder, key string) (*corev1.Secret, error) {
+// doRemediation moves the whole ConfigMap key of the candidate into a Secret.
+// Candidates found in the same key (e.g. nested values or files of a binary container) share the same Secret.
+func (rc *recCtx) doRemediation(c candidate) (*corev1.Secret, error) {
 	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{Name: b.Name, Namespace: rc.configMap.Namespace},
-		StringData: map[string]string{key: rc.configMap.Data[key]},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      v1alpha1.NewExposedSecretName(rc.configMap, c.key),
+			Namespace: rc.configMap.Namespace,
+			Labels:    map[string]string{v1alpha1.LabelManagedBy: v1alpha1.ManagedBy},
+		},
+	}
+	if !c.present(rc.configMap) {
+		// The key was already moved while remediating another candidate of the same key.
+		rc.log.DebugContext(rc.ctx, "Key already remediated", "key", c.key)
+		return secret, nil
+	}
+
+	if c.binary {
+		secret.Data = map[string][]byte{c.key: rc.configMap.BinaryData[c.key]}
+	} else {
+		secret.StringData = map[string]string{c.key: rc.configMap.Data[c.key]}
 	}
 	if err := rc.createOrUpdate(secret); err != nil {
 		rc.log.ErrorContext(rc.ctx, "Failed to create or update Secret", "error", err)
@@ -185,46 +387,83 @@ func (rc *recCtx) doRemediation(b *v1alpha1.ExposedSecretBuilder, key string) (*
 	rc.log.InfoContext(rc.ctx, "Secret created")
 	SecretsRemediated.WithLabelValues(rc.configMap.Namespace).Inc()
 
-	if rc.policy.Spec.EnableConfigMapMutation {
-		if err := rc.autoRemediateConfigMap(secret, key); err != nil {
+	if rc.policy.Spec.EnableConfigMapMutation || rc.admission {
+		// Workloads are rewired before the key is removed, so a failure is retried while the key is still present.
+		if rc.policy.Spec.EnableWorkloadRewiring {
+			if err := rc.rewireWorkloads(secret, c); err != nil {
+				rc.log.ErrorContext(rc.ctx, "Failed to rewire workloads", "error", err)
+				return nil, fmt.Errorf("failed to rewire workloads: %w", err)
+			}
+		}
+		if err := rc.autoRemediateConfigMap(secret, c); err != nil {
 			rc.log.ErrorContext(rc.ctx, "Failed to update ConfigMap", "error", err)
 			return nil, fmt.Errorf("failed to update ConfigMap: %w", err)
 		}
-		rc.log.InfoContext(rc.ctx, "Auto-remediated ConfigMap", "key", key)
+		rc.log.InfoContext(rc.ctx, "Auto-remediated ConfigMap", "key", c.key)
 		ConfigMapsMutated.WithLabelValues(rc.configMap.Namespace).Inc()
-	}
-	return secret, nil
-}
-
-// findSecretKeys returns all keys in the ConfigMap whose values match the scanner's secret pattern.
-func (rc *recCtx) findSecretKeys() []string {
-	var keys []string
-	for key, value := range rc.configMap.Data {
-		if rc.scanner.IsSecret(value) {
-			keys = append(keys, key)
+		if !rc.inPlace {
+			rc.event(rc.configMap, corev1.EventTypeNormal, ReasonConfigMapMutated, actionRemediate, "Key %q moved to Secret %q", c.key, secret.Name)
 		}
 	}
-	return keys
+	return secret, nil
 }
 
 // autoRemediateConfigMap removes the secret key from the ConfigMap, annotates it,
 // and updates the ConfigMap resource in the cluster.
-func (rc *recCtx) autoRemediateConfigMap(secret *corev1.Secret, key string) error {
-	rem := rc.configMap.DeepCopy()
-	if rem.Annotations == nil {
-		rem.Annotations = map[string]string{}
+// On admission, the ConfigMap is only mutated in place and stored by the API server.
+func (rc *recCtx) autoRemediateConfigMap(secret *corev1.Secret, c candidate) error {
+	if rc.inPlace {
+		stripKey(rc.configMap, secret.Name, c)
+		return nil
 	}
-	rem.Annotations[v1alpha1.AnnotationExposedSecret] = secret.Name
-	delete(rem.Data, key)
 
+	rem := rc.configMap.DeepCopy()
+	stripKey(rem, secret.Name, c)
 	if err := rc.cl.Update(rc.ctx, rem); err != nil {
 		return err
 	}
+	// Keep working on the updated ConfigMap so subsequent
+	// mutations are based on the latest resource version.
+	rc.configMap = rem
 	return nil
 }
 
+// unmarkRemediation removes [v1alpha1.AnnotationRemediate] from the ConfigMap once its secrets were remediated,
+// or if the policy doesn't remediate on admission.
+func (rc *recCtx) unmarkRemediation() error {
+	if _, ok := rc.configMap.Annotations[v1alpha1.AnnotationRemediate]; !ok {
+		return nil
+	}
+
+	cm := rc.configMap.DeepCopy()
+	delete(cm.Annotations, v1alpha1.AnnotationRemediate)
+	if err := rc.cl.Update(rc.ctx, cm); err != nil {
+		return err
+	}
+	rc.configMap = cm
+	return nil
+}
+
+// stripKey removes the key of the candidate from the ConfigMap and annotates it with the Secret now holding the key.
+func stripKey(cm *corev1.ConfigMap, secretName string, c candidate) {
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[v1alpha1.AnnotationExposedSecret] = secretName
+	if c.binary {
+		delete(cm.BinaryData, c.key)
+	} else {
+		delete(cm.Data, c.key)
+	}
+}
+
 // createOrUpdate creates or updates the given object in the cluster.
-func (rc *recCtx) createOrUpdate(obj client.Object) error {
+// The object is built from scratch, so the metadata of an existing object is kept:
+// its owner references and finalizers, and its labels and annotations the object doesn't set,
+// except for the managed keys, which are removed unless the object sets them.
+// The object therefore doesn't overwrite the ownership set by [recCtx.reconcileOwnership]
+// or the labels and annotations added by users.
+func (rc *recCtx) createOrUpdate(obj client.Object, managed ...string) error {
 	if obj == nil {
 		return stderrors.New("object is nil")
 	}
@@ -245,5 +484,50 @@ func (rc *recCtx) createOrUpdate(obj client.Object) error {
 
 	// Preserve the resource version to ensure the update is applied correctly.
 	obj.SetResourceVersion(existing.GetResourceVersion())
+	obj.SetOwnerReferences(mergeOwnerReferences(existing.GetOwnerReferences(), obj.GetOwnerReferences()))
+	obj.SetFinalizers(mergeFinalizers(existing.GetFinalizers(), obj.GetFinalizers()))
+	obj.SetLabels(mergeMetadata(existing.GetLabels(), obj.GetLabels(), managed))
+	obj.SetAnnotations(mergeMetadata(existing.GetAnnotations(), obj.GetAnnotations(), managed))
 	return rc.cl.Update(rc.ctx, obj)
 }
+
+// mergeOwnerReferences returns the existing owner references and the ones of the references with a new owner.
+// A new controller reference is dropped if the existing references already have a controller.
+func mergeOwnerReferences(existing, refs []metav1.OwnerReference) []metav1.OwnerReference {
+	merged := slices.Clone(existing)
+	for _, ref := range refs {
+		owned := slices.ContainsFunc(merged, func(r metav1.OwnerReference) bool { return r.UID == ref.UID })
+		controlled := ptr.Deref(ref.Controller, false) && slices.ContainsFunc(merged, func(r metav1.OwnerReference) bool {
+			return ptr.Deref(r.Controller, false)
+		})
+		if !owned && !controlled {
+			merged = append(merged, ref)
+		}
+	}
+	return merged
+}
+
+// mergeFinalizers returns the existing finalizers and the ones missing from them.
+func mergeFinalizers(existing, finalizers []string) []string {
+	merged := slices.Clone(existing)
+	for _, f := range finalizers {
+		if !slices.Contains(merged, f) {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
+
+// mergeMetadata returns the existing labels or annotations overwritten by the given ones.
+// Existing managed keys are dropped, so they are removed if they are no longer set.
+func mergeMetadata(existing, values map[string]string, managed []string) map[string]string {
+	if len(existing) == 0 {
+		return values
+	}
+	merged := maps.Clone(existing)
+	for _, k := range managed {
+		delete(merged, k)
+	}
+	maps.Copy(merged, values)
+	return merged
+}
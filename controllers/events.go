@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+)
+
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
+// Reasons of the Events emitted by the operator.
+const (
+	// ReasonSecretDetected is emitted on a ConfigMap if a secret was detected in it.
+	ReasonSecretDetected = "SecretDetected"
+	// ReasonSecretRemediated is emitted on a ConfigMap if a secret was moved to a Secret.
+	ReasonSecretRemediated = "SecretRemediated"
+	// ReasonConfigMapMutated is emitted on a ConfigMap if a key was removed from it.
+	ReasonConfigMapMutated = "ConfigMapMutated"
+	// ReasonPhaseChanged is emitted on an ExposedSecret if it moved to another phase.
+	ReasonPhaseChanged = "PhaseChanged"
+	// ReasonInvalidRules is emitted on a policy if the scanner skips some of its custom rules.
+	ReasonInvalidRules = "InvalidRules"
+	// ReasonPepperMissing is emitted on a policy if its keyed hash algorithm has no pepper and values are masked instead.
+	ReasonPepperMissing = "PepperMissing"
+)
+
+// Actions of the Events emitted by the operator.
+const (
+	actionScan      = "Scan"
+	actionRemediate = "Remediate"
+	actionReconcile = "Reconcile"
+	actionValidate  = "Validate"
+)
+
+// noopRecorder discards all Events. It is used if no recorder was configured.
+var noopRecorder events.EventRecorder = &events.FakeRecorder{}
+
+// event emits an Event regarding the object.
+// Notes are visible to everyone allowed to read Events, so they must never contain secret values.
+func (rc *recCtx) event(obj runtime.Object, eventType, reason, action, note string, args ...any) {
+	rc.recorder.Eventf(obj, nil, eventType, reason, action, note, args...)
+}
+
+// phaseChanged emits an Event on the [v1alpha1.ExposedSecret] if it moved to another phase.
+func (rc *recCtx) phaseChanged(es *v1alpha1.ExposedSecret, from v1alpha1.Phase) {
+	if es.Status.Phase == from {
+		return
+	}
+	rc.event(es, corev1.EventTypeNormal, ReasonPhaseChanged, actionReconcile, "Phase changed to %s: %s", es.Status.Phase, es.Status.Message)
+}
+
+// findingEvents emits the Events on the ConfigMap for a finding that moved to another phase.
+func (rc *recCtx) findingEvents(es *v1alpha1.ExposedSecret, from v1alpha1.Phase) {
+	if es.Status.Phase == from {
+		return
+	}
+	switch es.Status.Phase {
+	case v1alpha1.PhaseDetected:
+		rc.event(rc.configMap, corev1.EventTypeWarning, ReasonSecretDetected, actionScan,
+			"Secret detected at %q by rule %q with severity %s, see ExposedSecret %q",
+			es.Status.Location(), es.Status.RuleID, es.Spec.Severity, es.Name)
+	case v1alpha1.PhaseRemediated:
+		if es.Status.CreatedSecretRef == nil {
+			return
+		}
+		rc.event(rc.configMap, corev1.EventTypeNormal, ReasonSecretRemediated, actionRemediate,
+			"Secret at %q moved to Secret %q, see ExposedSecret %q",
+			es.Status.Location(), es.Status.CreatedSecretRef.Name, es.Name)
+	}
+}
+
+// policyEvents emits the Events on the policy for the conditions that changed since the old status,
+// so a degraded policy isn't reported again on every reconciliation of a ConfigMap it applies to.
+func policyEvents(rec events.EventRecorder, policy runtime.Object, old, status *v1alpha1.ScanPolicyStatus) {
+	if conditionChanged(old, status, v1alpha1.ConditionRulesValid) {
+		invalidRulesEvent(rec, policy, status)
+	}
+	if conditionChanged(old, status, v1alpha1.ConditionDegraded) {
+		pepperMissingEvent(rec, policy, status)
+	}
+}
+
+// conditionChanged reports whether the condition of the given type changed its status or reason,
+// or was observed for another generation of the policy.
+func conditionChanged(old, status *v1alpha1.ScanPolicyStatus, conditionType string) bool {
+	prev := meta.FindStatusCondition(old.Conditions, conditionType)
+	cur := meta.FindStatusCondition(status.Conditions, conditionType)
+	if prev == nil || cur == nil {
+		return prev != cur
+	}
+	return prev.Status != cur.Status || prev.Reason != cur.Reason || prev.ObservedGeneration != cur.ObservedGeneration
+}
+
+// invalidRulesEvent emits an Event on the policy if the scanner skips some of the custom rules of its configuration,
+// see [v1alpha1.ConditionRulesValid].
+func invalidRulesEvent(rec events.EventRecorder, policy runtime.Object, status *v1alpha1.ScanPolicyStatus) {
+	if c := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionRulesValid); c != nil && c.Status == metav1.ConditionFalse {
+		rec.Eventf(policy, nil, corev1.EventTypeWarning, ReasonInvalidRules, actionValidate, "%s: %s", status.Message, c.Message)
+	}
+}
+
+// pepperMissingEvent emits an Event on the policy if its keyed hash algorithm has no pepper,
+// see [v1alpha1.ReasonPepperMissing].
+func pepperMissingEvent(rec events.EventRecorder, policy runtime.Object, status *v1alpha1.ScanPolicyStatus) {
+	if c := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionDegraded); c != nil && c.Reason == v1alpha1.ReasonPepperMissing {
+		rec.Eventf(policy, nil, corev1.EventTypeWarning, ReasonPepperMissing, actionValidate, "%s", status.Message)
+	}
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=secretdetection.lvlcn-t.dev,resources=secretexceptions,verbs=get;list;watch
+
+// loadExceptions loads the [v1alpha1.SecretException] resources in the namespace of the ConfigMap.
+func (rc *recCtx) loadExceptions() error {
+	var list v1alpha1.SecretExceptionList
+	if err := rc.cl.List(rc.ctx, &list, client.InNamespace(rc.configMap.Namespace)); err != nil {
+		rc.log.ErrorContext(rc.ctx, "Failed to list SecretExceptions", "error", err)
+		return fmt.Errorf("failed to list SecretExceptions: %w", err)
+	}
+	rc.exceptions = list.Items
+	return nil
+}
+
+// exception returns the active [v1alpha1.SecretException] matching the candidate, or nil if there is none.
+// If multiple exceptions match, the one expiring last is returned. The ConfigMap is requeued
+// for the time it expires, so the finding is re-opened afterwards.
+func (rc *recCtx) exception(b *v1alpha1.ExposedSecretBuilder, c candidate) *v1alpha1.SecretException {
+	now := time.Now()
+	b.Fingerprint()
+
+	var match *v1alpha1.SecretException
+	for i := range rc.exceptions {
+		e := &rc.exceptions[i]
+		if !e.Active(now) {
+			continue
+		}
+		ok, err := e.Matches(rc.configMap, &b.Status, c.ruleIDs())
+		if err != nil {
+			rc.log.WarnContext(rc.ctx, "Invalid ConfigMap selector of SecretException, skipping it", "SecretException", e.Name, "error", err)
+			continue
+		}
+		if ok && (match == nil || cmp.Or(e.Spec.ExpiresAt.Compare(match.Spec.ExpiresAt.Time), cmp.Compare(match.Name, e.Name)) > 0) {
+			match = e
+		}
+	}
+
+	if match != nil {
+		rc.requeue(match.Spec.ExpiresAt.Sub(now))
+	}
+	return match
+}
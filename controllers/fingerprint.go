@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fingerprintInput is everything a reconciliation of a ConfigMap depends on.
+// If it didn't change since the last successful reconciliation, the ConfigMap is skipped.
+type fingerprintInput struct {
+	UID        types.UID         `json:"uid"`
+	Data       map[string]string `json:"data"`
+	BinaryData map[string][]byte `json:"binaryData"`
+	// Labels are matched by the ConfigMap selectors of the policy and the SecretExceptions.
+	Labels map[string]string `json:"labels"`
+	// Skip is the annotation opting the ConfigMap out of scanning.
+	Skip string `json:"skip"`
+	// Remediated is the annotation pointing to the Secret holding the remediated keys.
+	Remediated string `json:"remediated"`
+	// Remediate is the annotation marking the ConfigMap for remediation on admission.
+	Remediate  string   `json:"remediate"`
+	Finalizers []string `json:"finalizers"`
+	// Policy is the hash of the applied policy, see [policyHash].
+	Policy string `json:"policy"`
+	// Exposed maps the names of the ConfigMap's ExposedSecrets to their generations,
+	// so changes by the user (e.g. a revert) are reconciled.
+	Exposed map[string]int64 `json:"exposed"`
+	// Exceptions maps the names of the SecretExceptions in the namespace to their generations.
+	Exceptions map[string]int64 `json:"exceptions"`
+}
+
+// fingerprint returns the fingerprint of the ConfigMap's data, the applied policy, the ConfigMap's ExposedSecrets
+// and the SecretExceptions in its namespace.
+func (r *ConfigMapReconciler) fingerprint(ctx context.Context, cm *corev1.ConfigMap, policyHash string) (string, error) {
+	var list v1alpha1.ExposedSecretList
+	err := r.List(ctx, &list, client.InNamespace(cm.Namespace), client.MatchingFields{IndexExposedSecretConfigMap: cm.Name})
+	if err != nil {
+		return "", fmt.Errorf("failed to list ExposedSecrets: %w", err)
+	}
+	var exceptions v1alpha1.SecretExceptionList
+	if err = r.List(ctx, &exceptions, client.InNamespace(cm.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list SecretExceptions: %w", err)
+	}
+
+	in := fingerprintInput{
+		UID:        cm.UID,
+		Data:       cm.Data,
+		BinaryData: cm.BinaryData,
+		Labels:     cm.Labels,
+		Skip:       cm.Annotations[v1alpha1.AnnotationSkip],
+		Remediated: cm.Annotations[v1alpha1.AnnotationExposedSecret],
+		Remediate:  cm.Annotations[v1alpha1.AnnotationRemediate],
+		Finalizers: cm.Finalizers,
+		Policy:     policyHash,
+		Exposed:    make(map[string]int64, len(list.Items)),
+		Exceptions: make(map[string]int64, len(exceptions.Items)),
+	}
+	for i := range list.Items {
+		in.Exposed[list.Items[i].Name] = list.Items[i].Generation
+	}
+	for i := range exceptions.Items {
+		in.Exceptions[exceptions.Items[i].Name] = exceptions.Items[i].Generation
+	}
+	return hash(in)
+}
+
+// policyHash returns the hash of the applied policy's spec and source.
+func policyHash(policy *appliedPolicy) (string, error) {
+	return hash(struct {
+		Source string                  `json:"source"`
+		Spec   v1alpha1.ScanPolicySpec `json:"spec"`
+	}{policy.source, policy.Spec})
+}
+
+// hash returns the hex encoded SHA-256 hash of the JSON representation of v.
+// Map keys are sorted by [json.Marshal], so the hash is stable.
+func hash(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fingerprint: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// scanned is the fingerprint of the last successful reconciliation of a ConfigMap.
+type scanned struct {
+	fingerprint string
+	at          time.Time
+}
+
+// unchanged reports whether the fingerprint matches the one of the last successful reconciliation of the ConfigMap.
+// Once the resync interval passed, the ConfigMap is rescanned anyway to apply new scanner rules.
+func (r *ConfigMapReconciler) unchanged(key types.NamespacedName, fp string) bool {
+	v, ok := r.fingerprints.Load(key)
+	if !ok {
+		return false
+	}
+	last := v.(scanned)
+	if resync := r.config.ResyncInterval; resync > 0 && time.Since(last.at) >= resync {
+		return false
+	}
+	return last.fingerprint == fp
+}
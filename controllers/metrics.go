@@ -5,14 +5,27 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+// Defines the reasons a ConfigMap or key is skipped, see [ScansSkipped]
+const (
+	skipAnnotation        = "annotation"
+	skipConfigMapName     = "configmap_name"
+	skipConfigMapSelector = "configmap_selector"
+	skipExcludedKey       = "excluded_key"
+)
+
 // Defines the stages during the reconciliation process
 const (
 	stageLoadPolicy   = "load_policy"
 	stageGetConfigMap = "get_configmap"
 	stageCtxInit      = "ctx_init"
+	stageScan         = "scan"
 	stageProcessKey   = "process_key"
 	stageSideEffect   = "side_effect"
 	stageRemediate    = "remediate_secret"
+	stageRevert       = "revert_secret"
+	stageResolve      = "resolve_secret"
+	stageOwnership    = "ownership"
+	stageMigrate      = "migrate"
 )
 
 var (
@@ -25,6 +38,24 @@ var (
 		[]string{"namespace"},
 	)
 
+	// ScansSkipped are the total ConfigMaps and keys skipped by exclusions, by the reason they were skipped
+	ScansSkipped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "secret_detection_skipped_total",
+			Help: "Total number of ConfigMaps and keys skipped by exclusions",
+		},
+		[]string{"namespace", "reason"},
+	)
+
+	// ReconcilesSkipped are the total reconciliations skipped, because neither the ConfigMap nor its policy changed
+	ReconcilesSkipped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "secret_detection_reconciles_skipped_total",
+			Help: "Total number of ConfigMap reconcile loops skipped because nothing changed",
+		},
+		[]string{"namespace"},
+	)
+
 	// ReconcileDuration is a histogram of reconcile durations
 	ReconcileDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -72,6 +103,33 @@ var (
 		[]string{"namespace"},
 	)
 
+	// SecretsResolved are the total secrets resolved because they are no longer part of their ConfigMap
+	SecretsResolved = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "secret_detection_secrets_resolved_total",
+			Help: "Total number of secrets no longer found in their ConfigMap",
+		},
+		[]string{"namespace"},
+	)
+
+	// WorkloadsRewired are the total workloads rewired to a remediated Secret, labeled by the workload kind
+	WorkloadsRewired = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "secret_detection_workloads_rewired_total",
+			Help: "Total number of workloads rewired to read remediated keys from Secrets",
+		},
+		[]string{"namespace", "kind"},
+	)
+
+	// ConfigMapAdmissions are the total ConfigMaps reviewed on admission, labeled by the decision
+	ConfigMapAdmissions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "secret_detection_configmap_admissions_total",
+			Help: "Total number of ConfigMaps reviewed on admission",
+		},
+		[]string{"namespace", "decision"},
+	)
+
 	// ReconcileErrors are the total errors encountered during reconciliation
 	ReconcileErrors = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -85,11 +143,16 @@ var (
 func init() { //nolint:gochecknoinits // Common pattern for controller-runtime
 	metrics.Registry.MustRegister(
 		ConfigMapReconciles,
+		ReconcilesSkipped,
+		ScansSkipped,
 		ReconcileDuration,
 		KeysScanned,
 		SecretsDetected,
 		SecretsRemediated,
 		ConfigMapsMutated,
+		SecretsResolved,
+		WorkloadsRewired,
+		ConfigMapAdmissions,
 		ReconcileErrors,
 	)
 }
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// migrateNames renames the [v1alpha1.ExposedSecret] resources of the ConfigMap that were named
+// by an earlier naming scheme, see [v1alpha1.NewExposedSecretName]. Resources can't be renamed,
+// so a copy is created under the new name and the old resource is deleted afterwards.
+// Secrets created by a remediation keep their name, since workloads may already read from them.
+func (rc *recCtx) migrateNames() error {
+	exposed, err := rc.listExposedSecrets()
+	if err != nil {
+		return err
+	}
+
+	for i := range exposed {
+		es := &exposed[i]
+		name := v1alpha1.NewExposedSecretName(rc.configMap, es.Status.Key, es.Status.InnerPath, es.Status.Path)
+		if es.Name == name {
+			continue
+		}
+		if err = rc.rename(es, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rename copies the [v1alpha1.ExposedSecret] to a resource with the given name and deletes it.
+// If a resource with the new name already exists, it is kept and only the old one is deleted.
+func (rc *recCtx) rename(es *v1alpha1.ExposedSecret, name string) error {
+	annotations := maps.Clone(es.Annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[v1alpha1.AnnotationKey] = es.Status.Key
+
+	renamed := &v1alpha1.ExposedSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       es.Namespace,
+			Labels:          es.Labels,
+			Annotations:     annotations,
+			OwnerReferences: es.OwnerReferences,
+		},
+		Spec: es.Spec,
+	}
+	status := es.Status
+	switch err := rc.cl.Create(rc.ctx, renamed); {
+	case errors.IsAlreadyExists(err):
+		rc.log.DebugContext(rc.ctx, "Renamed ExposedSecret already exists", "ExposedSecret", es.Name, "name", name)
+	case err != nil:
+		rc.log.ErrorContext(rc.ctx, "Failed to create renamed ExposedSecret", "ExposedSecret", es.Name, "error", err)
+		return fmt.Errorf("failed to create renamed ExposedSecret: %w", err)
+	default:
+		renamed.Status = status
+		if err = rc.cl.Status().Update(rc.ctx, renamed); err != nil {
+			rc.log.ErrorContext(rc.ctx, "Failed to update ExposedSecret status", "ExposedSecret", name, "error", err)
+			return fmt.Errorf("failed to update ExposedSecret status: %w", err)
+		}
+		// The Secret is owned by the renamed resource before the old one is deleted, so it isn't garbage collected.
+		if err = rc.ownSecret(renamed); err != nil {
+			return err
+		}
+	}
+
+	if err := rc.cl.Delete(rc.ctx, es); client.IgnoreNotFound(err) != nil {
+		rc.log.ErrorContext(rc.ctx, "Failed to delete renamed ExposedSecret", "ExposedSecret", es.Name, "error", err)
+		return fmt.Errorf("failed to delete renamed ExposedSecret: %w", err)
+	}
+	rc.log.InfoContext(rc.ctx, "Renamed ExposedSecret", "ExposedSecret", es.Name, "name", name)
+	return nil
+}
+
+// rehashFindings re-hashes the detected values of the ConfigMap's [v1alpha1.ExposedSecret] resources that were
+// reported as plaintext by [v1alpha1.AlgorithmNone], e.g. after the policy's algorithm changed or plaintext was
+// disallowed. Hashes can't be reverted, so values hashed by another algorithm are kept; findings still present
+// in the ConfigMap are re-hashed from their current value when they are processed anyway.
+func (rc *recCtx) rehashFindings() error {
+	algorithm := rc.policy.Spec.HashAlgorithm
+	if algorithm == v1alpha1.AlgorithmNone {
+		return nil
+	}
+	exposed, err := rc.listExposedSecrets()
+	if err != nil {
+		return err
+	}
+
+	for i := range exposed {
+		es := &exposed[i]
+		value := es.Status.DetectedValue
+		if value == "" || v1alpha1.HashAlgorithmOf(value) != v1alpha1.AlgorithmNone {
+			continue
+		}
+		plain, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			rc.log.DebugContext(rc.ctx, "Detected value is not base64 encoded, skipping re-hash", "ExposedSecret", es.Name)
+			continue
+		}
+
+		es.Status.DetectedValue = algorithm.HashWithPepper(string(plain), rc.pepper)
+		if err = rc.cl.Status().Update(rc.ctx, es); err != nil {
+			rc.log.ErrorContext(rc.ctx, "Failed to update ExposedSecret status", "ExposedSecret", es.Name, "error", err)
+			return fmt.Errorf("failed to update ExposedSecret status: %w", err)
+		}
+		rc.log.InfoContext(rc.ctx, "Re-hashed detected value of ExposedSecret", "ExposedSecret", es.Name, "algorithm", algorithm)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps/finalizers,verbs=update
+// +kubebuilder:rbac:groups=secretdetection.lvlcn-t.dev,resources=exposedsecrets/finalizers,verbs=update
+
+// reconcileOwnership links the resources created for the ConfigMap with owner references:
+// every [v1alpha1.ExposedSecret] is controlled by the ConfigMap it reports, and every
+// remediated Secret is owned and labeled by the ExposedSecrets reporting its key.
+// Deleting the ConfigMap therefore garbage collects its findings, unless the policy
+// retains them, see [v1alpha1.RetentionRetain].
+func (rc *recCtx) reconcileOwnership() error {
+	exposed, err := rc.listExposedSecrets()
+	if err != nil {
+		return err
+	}
+
+	for i := range exposed {
+		es := &exposed[i]
+		if err = rc.ownSecret(es); err != nil {
+			return err
+		}
+		if err = rc.ownExposedSecret(es); err != nil {
+			return err
+		}
+	}
+
+	retain := rc.policy.Spec.FindingRetention == v1alpha1.RetentionRetain
+	return rc.updateFinalizer(retain && len(exposed) > 0)
+}
+
+// ownExposedSecret sets the ConfigMap as the controller of the [v1alpha1.ExposedSecret].
+// ExposedSecrets already controlled by another resource are left untouched.
+func (rc *recCtx) ownExposedSecret(es *v1alpha1.ExposedSecret) error {
+	if metav1.IsControlledBy(es, rc.configMap) || rc.configMap.UID == "" {
+		return nil
+	}
+	if owner := metav1.GetControllerOf(es); owner != nil {
+		rc.log.WarnContext(rc.ctx, "ExposedSecret is controlled by another resource", "ExposedSecret", es.Name, "owner", owner.Name)
+		return nil
+	}
+
+	if err := rc.controlExposedSecret(es); err != nil {
+		return err
+	}
+	if err := rc.cl.Update(rc.ctx, es); err != nil {
+		rc.log.ErrorContext(rc.ctx, "Failed to update ExposedSecret", "ExposedSecret", es.Name, "error", err)
+		return fmt.Errorf("failed to update ExposedSecret: %w", err)
+	}
+	rc.log.DebugContext(rc.ctx, "Set owner reference of ExposedSecret", "ExposedSecret", es.Name)
+	return nil
+}
+
+// controlExposedSecret sets the ConfigMap as the controller of the [v1alpha1.ExposedSecret] without updating it,
+// so a newly built ExposedSecret is already owned when it is written.
+// ExposedSecrets with a controller and ConfigMaps not stored yet, i.e. on admission, are skipped.
+func (rc *recCtx) controlExposedSecret(es *v1alpha1.ExposedSecret) error {
+	if rc.configMap.UID == "" || metav1.GetControllerOf(es) != nil {
+		return nil
+	}
+	if err := controllerutil.SetControllerReference(rc.configMap, es, rc.cl.Scheme()); err != nil {
+		return fmt.Errorf("failed to set owner reference: %w", err)
+	}
+	return nil
+}
+
+// ownSecret adds the [v1alpha1.ExposedSecret] to the owners of the Secret it created
+// and labels the Secret with the ExposedSecret. A Secret holding multiple secrets of
+// the same key is owned by all their ExposedSecrets, so it is deleted with the last one.
+func (rc *recCtx) ownSecret(es *v1alpha1.ExposedSecret) error {
+	if es.Status.CreatedSecretRef == nil {
+		return nil
+	}
+
+	var secret corev1.Secret
+	key := client.ObjectKey{Namespace: es.Namespace, Name: es.Status.CreatedSecretRef.Name}
+	if err := rc.cl.Get(rc.ctx, key, &secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		rc.log.ErrorContext(rc.ctx, "Failed to get Secret", "Secret", key.Name, "error", err)
+		return fmt.Errorf("failed to get Secret: %w", err)
+	}
+
+	orig := secret.DeepCopy()
+	if err := controllerutil.SetOwnerReference(es, &secret, rc.cl.Scheme()); err != nil {
+		return fmt.Errorf("failed to set owner reference: %w", err)
+	}
+	labels := maps.Clone(secret.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[v1alpha1.LabelManagedBy] = v1alpha1.ManagedBy
+	// The first ExposedSecret of the key labels the Secret, names exceeding the label value limit are skipped.
+	if _, ok := labels[v1alpha1.LabelExposedSecret]; !ok && len(validation.IsValidLabelValue(es.Name)) == 0 {
+		labels[v1alpha1.LabelExposedSecret] = es.Name
+	}
+	secret.Labels = labels
+
+	if equalOwnership(orig, &secret) {
+		return nil
+	}
+	if err := rc.cl.Update(rc.ctx, &secret); err != nil {
+		rc.log.ErrorContext(rc.ctx, "Failed to update Secret", "Secret", key.Name, "error", err)
+		return fmt.Errorf("failed to update Secret: %w", err)
+	}
+	rc.log.DebugContext(rc.ctx, "Set owner reference of Secret", "Secret", key.Name, "ExposedSecret", es.Name)
+	return nil
+}
+
+// updateFinalizer adds the [v1alpha1.FinalizerRetainFindings] finalizer to the ConfigMap if want
+// is true and removes it otherwise.
+func (rc *recCtx) updateFinalizer(want bool) error {
+	if controllerutil.ContainsFinalizer(rc.configMap, v1alpha1.FinalizerRetainFindings) == want {
+		return nil
+	}
+
+	cm := rc.configMap.DeepCopy()
+	if want {
+		controllerutil.AddFinalizer(cm, v1alpha1.FinalizerRetainFindings)
+	} else {
+		controllerutil.RemoveFinalizer(cm, v1alpha1.FinalizerRetainFindings)
+	}
+	if err := rc.cl.Update(rc.ctx, cm); err != nil {
+		rc.log.ErrorContext(rc.ctx, "Failed to update ConfigMap finalizers", "error", err)
+		return fmt.Errorf("failed to update ConfigMap finalizers: %w", err)
+	}
+	rc.configMap = cm
+	return nil
+}
+
+// releaseFindings is called for a ConfigMap that is being deleted. If the ConfigMap retains its
+// findings, the owner references to the ConfigMap are removed from its [v1alpha1.ExposedSecret]
+// resources, so they outlive it, and they are resolved. The finalizer is removed afterwards,
+// so the ConfigMap can be deleted.
+func (rc *recCtx) releaseFindings() error {
+	if !controllerutil.ContainsFinalizer(rc.configMap, v1alpha1.FinalizerRetainFindings) {
+		return nil
+	}
+
+	exposed, err := rc.listExposedSecrets()
+	if err != nil {
+		return err
+	}
+	for i := range exposed {
+		es := &exposed[i]
+		if !metav1.IsControlledBy(es, rc.configMap) {
+			continue
+		}
+		if err = controllerutil.RemoveOwnerReference(rc.configMap, es, rc.cl.Scheme()); err != nil {
+			return fmt.Errorf("failed to remove owner reference: %w", err)
+		}
+		if err = rc.cl.Update(rc.ctx, es); err != nil {
+			rc.log.ErrorContext(rc.ctx, "Failed to update ExposedSecret", "ExposedSecret", es.Name, "error", err)
+			return fmt.Errorf("failed to update ExposedSecret: %w", err)
+		}
+		rc.log.InfoContext(rc.ctx, "Retained ExposedSecret of deleted ConfigMap", "ExposedSecret", es.Name)
+	}
+
+	if err = rc.resolveFindings(nil); err != nil {
+		return err
+	}
+	return rc.updateFinalizer(false)
+}
+
+// equalOwnership reports whether the owner references and labels of both objects are equal.
+func equalOwnership(a, b metav1.Object) bool {
+	if !maps.Equal(a.GetLabels(), b.GetLabels()) || len(a.GetOwnerReferences()) != len(b.GetOwnerReferences()) {
+		return false
+	}
+	for i, ref := range a.GetOwnerReferences() {
+		if ref.UID != b.GetOwnerReferences()[i].UID {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=secretdetection.lvlcn-t.dev,resources=clusterscanpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=secretdetection.lvlcn-t.dev,resources=clusterscanpolicies/status,verbs=get;update;patch
+
+// appliedPolicy is the [v1alpha1.ScanPolicy] applied to a namespace.
+type appliedPolicy struct {
+	*v1alpha1.ScanPolicy
+	// source identifies where the policy came from, see [v1alpha1.PolicySource].
+	source string
+	// scanPolicies are the ScanPolicies merged into the applied policy, sorted by name.
+	scanPolicies []v1alpha1.ScanPolicy
+	// clusterPolicy is the ClusterScanPolicy the applied policy was derived from.
+	clusterPolicy *v1alpha1.ClusterScanPolicy
+}
+
+// resolveScanPolicy resolves the ScanPolicy for the given namespace without modifying any resources.
+// It uses the first match of:
+//
+//  1. the ScanPolicies in the namespace, merged if there are multiple ones,
+//  2. the ClusterScanPolicy with the highest priority matching the namespace,
+//  3. the default policy of the operator's configuration.
+func (r *ConfigMapReconciler) resolveScanPolicy(ctx context.Context, namespace string) (*appliedPolicy, error) {
+	log := logr.FromContextAsSlogLogger(ctx)
+	var scanPolicies v1alpha1.ScanPolicyList
+	if err := r.List(ctx, &scanPolicies, client.InNamespace(namespace)); err != nil {
+		log.ErrorContext(ctx, "Failed to list ScanPolicies", "error", err)
+		return nil, err
+	}
+
+	if len(scanPolicies.Items) > 0 {
+		return mergeScanPolicies(ctx, scanPolicies.Items), nil
+	}
+
+	csp, err := r.loadClusterScanPolicy(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if csp != nil {
+		log.DebugContext(ctx, "No ScanPolicies found, using ClusterScanPolicy", "ClusterScanPolicy", csp.Name)
+		return &appliedPolicy{
+			ScanPolicy:    csp.ScanPolicyFor(namespace),
+			source:        v1alpha1.PolicySource("ClusterScanPolicy", csp.Name),
+			clusterPolicy: csp,
+		}, nil
+	}
+
+	log.DebugContext(ctx, "No ScanPolicies found, using default values")
+	return &appliedPolicy{ScanPolicy: r.config.ScanPolicy.DeepCopy(), source: v1alpha1.DefaultPolicySource}, nil
+}
+
+// mergeScanPolicies merges the given ScanPolicies into the policy applied to their namespace.
+func mergeScanPolicies(ctx context.Context, policies []v1alpha1.ScanPolicy) *appliedPolicy {
+	slices.SortFunc(policies, func(a, b v1alpha1.ScanPolicy) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	names := make([]string, 0, len(policies))
+	for i := range policies {
+		names = append(names, policies[i].Name)
+	}
+
+	applied := policies[0].DeepCopy()
+	if len(policies) > 1 {
+		logr.FromContextAsSlogLogger(ctx).DebugContext(ctx, "Multiple ScanPolicies found, merging them", "ScanPolicies", names)
+		applied.Spec = v1alpha1.MergeScanPolicies(policies...)
+	}
+	return &appliedPolicy{ScanPolicy: applied, source: v1alpha1.PolicySource("ScanPolicy", names...), scanPolicies: policies}
+}
+
+// policyStatusInterval is the minimum interval between status updates of a policy that would only
+// refresh its [v1alpha1.ScanPolicyStatus.LastProcessedTime], so reconciling many ConfigMaps
+// with the same policy doesn't update the policy's status every time.
+const policyStatusInterval = time.Minute
+
+// recordScanPolicy updates the status of the policies the applied policy was derived from.
+// If multiple ScanPolicies were merged, the effective spec is recorded on every one of them.
+// Events are only emitted if a condition changed, see [policyEvents].
+// Failing to update a status is logged but doesn't fail the reconciliation.
+func (r *ConfigMapReconciler) recordScanPolicy(ctx context.Context, policy *appliedPolicy) {
+	log := logr.FromContextAsSlogLogger(ctx)
+	now := metav1.Now()
+
+	var names []string
+	var effective *v1alpha1.ScanPolicySpec
+	if len(policy.scanPolicies) > 1 {
+		effective = &policy.Spec
+		for i := range policy.scanPolicies {
+			names = append(names, policy.scanPolicies[i].Name)
+		}
+	}
+	for i := range policy.scanPolicies {
+		old := &policy.scanPolicies[i]
+		sp := old.DeepCopy()
+		sp.Status.EffectiveSpec = effective.DeepCopy()
+		sp.Status.MergedPolicies = names
+		sp.Status.SetConditions(sp.Generation, &sp.Spec)
+		if r.pepperMissing(&sp.Spec) {
+			sp.Status.SetPepperMissing(sp.Generation, sp.Spec.HashAlgorithm)
+		}
+		if err := r.updatePolicyStatus(ctx, sp, &old.Status, &sp.Status, now); err != nil {
+			log.ErrorContext(ctx, "Failed to update ScanPolicy status", "ScanPolicy", sp.Name, "error", err)
+		}
+		policyEvents(r.recorder, sp, &old.Status, &sp.Status)
+	}
+
+	if policy.clusterPolicy != nil {
+		csp := policy.clusterPolicy.DeepCopy()
+		csp.Status.SetConditions(csp.Generation, &csp.Spec.ScanPolicySpec)
+		if r.pepperMissing(&csp.Spec.ScanPolicySpec) {
+			csp.Status.SetPepperMissing(csp.Generation, csp.Spec.HashAlgorithm)
+		}
+		if err := r.updatePolicyStatus(ctx, csp, &policy.clusterPolicy.Status, &csp.Status, now); err != nil {
+			log.ErrorContext(ctx, "Failed to update ClusterScanPolicy status", "error", err)
+		}
+		policyEvents(r.recorder, csp, &policy.clusterPolicy.Status, &csp.Status)
+	}
+}
+
+// updatePolicyStatus updates the status of the policy if it differs from the old one
+// or the old one was processed longer than [policyStatusInterval] ago.
+func (r *ConfigMapReconciler) updatePolicyStatus(ctx context.Context, obj client.Object, old, status *v1alpha1.ScanPolicyStatus, now metav1.Time) error {
+	status.LastProcessedTime = old.LastProcessedTime
+	if equality.Semantic.DeepEqual(old, status) && now.Sub(old.LastProcessedTime.Time) < policyStatusInterval {
+		return nil
+	}
+	status.LastProcessedTime = now
+	return r.Status().Update(ctx, obj)
+}
+
+// pepperMissing reports whether the policy uses a keyed hash algorithm without a configured pepper.
+// The values of its findings are masked instead, see [recCtx.initCtx].
+func (r *ConfigMapReconciler) pepperMissing(spec *v1alpha1.ScanPolicySpec) bool {
+	return spec.HashAlgorithm.Keyed() && r.config.Pepper.Get() == nil
+}
+
+// loadClusterScanPolicy returns the ClusterScanPolicy with the highest priority that matches the namespace.
+// It returns nil if no ClusterScanPolicy matches.
+// ClusterScanPolicies are ignored if the operator runs with namespaced Roles, see [config.Watch].
+func (r *ConfigMapReconciler) loadClusterScanPolicy(ctx context.Context, namespace string) (*v1alpha1.ClusterScanPolicy, error) {
+	if r.config.Watch.Namespaced {
+		return nil, nil
+	}
+	log := logr.FromContextAsSlogLogger(ctx)
+	var policies v1alpha1.ClusterScanPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		log.ErrorContext(ctx, "Failed to list ClusterScanPolicies", "error", err)
+		return nil, err
+	}
+	if len(policies.Items) == 0 {
+		return nil, nil
+	}
+
+	nsLabels, err := r.namespaceLabels(ctx, namespace)
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to get Namespace", "error", err)
+		return nil, err
+	}
+
+	var matching []*v1alpha1.ClusterScanPolicy
+	for i := range policies.Items {
+		p := &policies.Items[i]
+		ok, err := p.Matches(namespace, nsLabels)
+		if err != nil {
+			log.WarnContext(ctx, "Invalid namespace selector in ClusterScanPolicy", "ClusterScanPolicy", p.Name, "error", err)
+			continue
+		}
+		if ok {
+			matching = append(matching, p)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, nil
+	}
+
+	slices.SortFunc(matching, func(a, b *v1alpha1.ClusterScanPolicy) int {
+		return cmp.Or(cmp.Compare(b.Spec.Priority, a.Spec.Priority), cmp.Compare(a.Name, b.Name))
+	})
+	return matching[0].DeepCopy(), nil
+}
+
+// namespaceLabels returns the labels of the given namespace.
+// If the namespace can't be found, only the well-known name label is returned.
+func (r *ConfigMapReconciler) namespaceLabels(ctx context.Context, namespace string) (map[string]string, error) {
+	var ns corev1.Namespace
+	if err := r.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		ns.Labels = nil
+	}
+
+	nsLabels := make(map[string]string, len(ns.Labels)+1)
+	for k, v := range ns.Labels {
+		nsLabels[k] = v
+	}
+	nsLabels[corev1.LabelMetadataName] = namespace
+	return nsLabels, nil
+}
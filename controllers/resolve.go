@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=secretdetection.lvlcn-t.dev,resources=exposedsecrets,verbs=delete
+
+// IndexExposedSecretConfigMap is the field index of [v1alpha1.ExposedSecret] resources
+// by the name of the ConfigMap they report, see [IndexExposedSecretByConfigMap].
+const IndexExposedSecretConfigMap = "status.configMapRef.name"
+
+// IndexExposedSecretByConfigMap returns the name of the ConfigMap reported by the [v1alpha1.ExposedSecret].
+// It is used to index ExposedSecrets with [IndexExposedSecretConfigMap].
+func IndexExposedSecretByConfigMap(obj client.Object) []string {
+	es, ok := obj.(*v1alpha1.ExposedSecret)
+	if !ok || es.Status.ConfigMapReference.Name == "" {
+		return nil
+	}
+	return []string{es.Status.ConfigMapReference.Name}
+}
+
+// listExposedSecrets returns all [v1alpha1.ExposedSecret] resources reporting the ConfigMap.
+func (rc *recCtx) listExposedSecrets() ([]v1alpha1.ExposedSecret, error) {
+	var list v1alpha1.ExposedSecretList
+	err := rc.cl.List(rc.ctx, &list,
+		client.InNamespace(rc.configMap.Namespace),
+		client.MatchingFields{IndexExposedSecretConfigMap: rc.configMap.Name},
+	)
+	if err != nil {
+		rc.log.ErrorContext(rc.ctx, "Failed to list ExposedSecrets", "error", err)
+		return nil, fmt.Errorf("failed to list ExposedSecrets: %w", err)
+	}
+	return list.Items, nil
+}
+
+// release releases the [v1alpha1.ExposedSecret] resources of a ConfigMap that is being deleted, see [recCtx.releaseFindings].
+func (rc *recCtx) release(ctx context.Context) error {
+	rc.ctx = ctx
+	rc.log = logr.FromContextAsSlogLogger(ctx).With("ConfigMap", rc.configMap.Name)
+	if err := rc.releaseFindings(); err != nil {
+		rc.log.ErrorContext(ctx, "Failed to release ExposedSecrets", "error", err)
+		return fmt.Errorf("failed to release ExposedSecrets: %w", err)
+	}
+	return nil
+}
+
+// resolveDeleted resolves all [v1alpha1.ExposedSecret] resources of a ConfigMap that no longer exists.
+func (rc *recCtx) resolveDeleted(ctx context.Context) error {
+	rc.ctx = ctx
+	rc.log = logr.FromContextAsSlogLogger(ctx).With("ConfigMap", rc.configMap.Name)
+	return rc.resolveFindings(nil)
+}
+
+// resolveFindings moves all [v1alpha1.ExposedSecret] resources of the ConfigMap whose secret
+// wasn't found anymore to [v1alpha1.PhaseResolved]. The found map holds the names of the
+// ExposedSecrets whose secret is still part of the ConfigMap. Remediated secrets are kept,
+// since their key was removed on purpose. Resolved ExposedSecrets are garbage collected
+// once the resolved TTL expired.
+func (rc *recCtx) resolveFindings(found map[string]bool) error {
+	exposed, err := rc.listExposedSecrets()
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	for i := range exposed {
+		es := &exposed[i]
+		if found[es.Name] || es.Status.Phase == v1alpha1.PhaseRemediated {
+			continue
+		}
+
+		if from := es.Status.Phase; from != v1alpha1.PhaseResolved {
+			es.Status.Phase = v1alpha1.PhaseResolved
+			es.Status.Message = fmt.Sprintf("Secret no longer found in ConfigMap %q at %q", rc.configMap.Name, es.Status.Location())
+			es.Status.ResolvedTime = &now
+			es.Status.LastUpdateTime = now
+			es.Status.SetConditions(es.Generation)
+			// ExposedSecrets owned by a deleted ConfigMap may be garbage collected concurrently.
+			if err = rc.cl.Status().Update(rc.ctx, es); errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				rc.log.ErrorContext(rc.ctx, "Failed to update ExposedSecret status", "ExposedSecret", es.Name, "error", err)
+				return fmt.Errorf("failed to update ExposedSecret status: %w", err)
+			}
+			rc.log.InfoContext(rc.ctx, "Resolved ExposedSecret", "ExposedSecret", es.Name)
+			rc.phaseChanged(es, from)
+			SecretsResolved.WithLabelValues(rc.configMap.Namespace).Inc()
+		}
+
+		if err = rc.collectGarbage(es, now.Time); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectGarbage deletes the resolved [v1alpha1.ExposedSecret] if its TTL expired.
+// Otherwise, the ConfigMap is requeued for the time the TTL expires.
+func (rc *recCtx) collectGarbage(es *v1alpha1.ExposedSecret, now time.Time) error {
+	if rc.resolvedTTL <= 0 || es.Status.ResolvedTime == nil {
+		return nil
+	}
+
+	remaining := es.Status.ResolvedTime.Add(rc.resolvedTTL).Sub(now)
+	if remaining > 0 {
+		rc.requeue(remaining)
+		return nil
+	}
+
+	if err := rc.cl.Delete(rc.ctx, es); client.IgnoreNotFound(err) != nil {
+		rc.log.ErrorContext(rc.ctx, "Failed to delete resolved ExposedSecret", "ExposedSecret", es.Name, "error", err)
+		return fmt.Errorf("failed to delete resolved ExposedSecret: %w", err)
+	}
+	rc.log.InfoContext(rc.ctx, "Deleted resolved ExposedSecret", "ExposedSecret", es.Name)
+	return nil
+}
@@ -2,18 +2,22 @@ package controllers
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
 	"github.com/lvlcn-t/secret-detection-operator/scanners"
 )
 
-// ActionResolver decides "policy vs user vs severity”
+// ActionResolver decides "policy vs user vs exception vs severity”
 type ActionResolver struct {
 	OverrideAction v1alpha1.Action
 	HasOverride    bool
 	DefaultPolicy  v1alpha1.Action
 	Severity       scanners.Severity
 	MinSeverity    scanners.Severity
+	// Exception is the active [v1alpha1.SecretException] matching the finding, if any.
+	// It takes precedence over the policy, but not over an action set by the user.
+	Exception *v1alpha1.SecretException
 }
 
 type ResolvedAction struct {
@@ -24,6 +28,15 @@ type ResolvedAction struct {
 }
 
 func (r ActionResolver) Resolve() ResolvedAction {
+	if r.HasOverride && r.OverrideAction == v1alpha1.ActionRevert {
+		return ResolvedAction{
+			Action:        v1alpha1.ActionRevert,
+			FinalPhase:    v1alpha1.PhaseReverted,
+			FinalSeverity: r.Severity,
+			Message:       "remediation reverted by user",
+		}
+	}
+
 	if r.HasOverride {
 		return ResolvedAction{
 			Action:        r.OverrideAction,
@@ -33,6 +46,16 @@ func (r ActionResolver) Resolve() ResolvedAction {
 		}
 	}
 
+	if e := r.Exception; e != nil {
+		return ResolvedAction{
+			Action:        v1alpha1.ActionIgnore,
+			FinalPhase:    v1alpha1.PhaseIgnored,
+			FinalSeverity: r.Severity,
+			Message: fmt.Sprintf("excepted by SecretException %q of %s until %s: %s",
+				e.Name, e.Spec.Owner, e.Spec.ExpiresAt.UTC().Format(time.RFC3339), e.Spec.Reason),
+		}
+	}
+
 	if r.Severity.Int() < r.MinSeverity.Int() {
 		return ResolvedAction{
 			Action:        v1alpha1.ActionIgnore,
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=delete
+
+// revertRemediations restores the keys of all [v1alpha1.ExposedSecret] resources of the ConfigMap
+// the user set to [v1alpha1.ActionRevert]. The key is restored from the created Secret,
+// the ConfigMap's [v1alpha1.AnnotationExposedSecret] annotation is removed (or points to another
+// remediated key of the ConfigMap) and the Secret is deleted if requested. Every ExposedSecret
+// sharing the Secret is moved to [v1alpha1.PhaseReverted], so secrets found in the same key
+// aren't remediated again.
+func (rc *recCtx) revertRemediations() error {
+	exposed, err := rc.listExposedSecrets()
+	if err != nil {
+		return err
+	}
+
+	// Multiple ExposedSecrets share a Secret if they were found in the same key.
+	reverts := map[string][]*v1alpha1.ExposedSecret{}
+	var names []string
+	for i := range exposed {
+		es := &exposed[i]
+		if es.Spec.Action != v1alpha1.ActionRevert || es.Status.Phase == v1alpha1.PhaseReverted {
+			continue
+		}
+		name := ""
+		if es.Status.CreatedSecretRef != nil {
+			name = es.Status.CreatedSecretRef.Name
+		}
+		if _, ok := reverts[name]; !ok {
+			names = append(names, name)
+		}
+		reverts[name] = append(reverts[name], es)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	slices.Sort(names)
+
+	cm := rc.configMap.DeepCopy()
+	restored := map[string]bool{}
+	messages := map[string]string{}
+	for _, name := range names {
+		if name == "" {
+			messages[name] = "remediation reverted by user, the secret was never moved"
+			continue
+		}
+		ok, err := rc.restoreKey(cm, name, reverts[name][0])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			messages[name] = fmt.Sprintf("remediation reverted by user, but Secret %q holding the key was not found", name)
+			continue
+		}
+		restored[name] = true
+		messages[name] = fmt.Sprintf("remediation reverted by user, key restored from Secret %q", name)
+	}
+
+	// Secrets found in the same key are reverted together, since they share the restored key.
+	for i := range exposed {
+		es := &exposed[i]
+		if es.Status.CreatedSecretRef == nil || es.Spec.Action == v1alpha1.ActionRevert {
+			continue
+		}
+		if name := es.Status.CreatedSecretRef.Name; len(reverts[name]) > 0 {
+			reverts[name] = append(reverts[name], es)
+		}
+	}
+
+	if cm.Annotations[v1alpha1.AnnotationExposedSecret] != "" && reverts[cm.Annotations[v1alpha1.AnnotationExposedSecret]] != nil {
+		if remaining := remediatedSecrets(exposed, reverts); len(remaining) > 0 {
+			cm.Annotations[v1alpha1.AnnotationExposedSecret] = remaining[0]
+		} else {
+			delete(cm.Annotations, v1alpha1.AnnotationExposedSecret)
+		}
+	}
+
+	if !reflect.DeepEqual(cm, rc.configMap) {
+		if err = rc.cl.Update(rc.ctx, cm); err != nil {
+			rc.log.ErrorContext(rc.ctx, "Failed to update ConfigMap", "error", err)
+			return fmt.Errorf("failed to update ConfigMap: %w", err)
+		}
+		rc.configMap = cm
+	}
+
+	for _, name := range names {
+		if !restored[name] || !deleteSecretOnRevert(reverts[name]) {
+			continue
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: rc.configMap.Namespace, Name: name}}
+		if err = rc.cl.Delete(rc.ctx, secret); client.IgnoreNotFound(err) != nil {
+			rc.log.ErrorContext(rc.ctx, "Failed to delete Secret", "Secret", name, "error", err)
+			return fmt.Errorf("failed to delete Secret: %w", err)
+		}
+		rc.log.InfoContext(rc.ctx, "Deleted reverted Secret", "Secret", name)
+	}
+
+	for _, name := range names {
+		for _, es := range reverts[name] {
+			if err = rc.markReverted(es, messages[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreKey restores the key of the ExposedSecret into the ConfigMap from the Secret with the given name.
+// A key that is still part of the ConfigMap is left untouched. It reports whether the key is part of the ConfigMap.
+func (rc *recCtx) restoreKey(cm *corev1.ConfigMap, secretName string, es *v1alpha1.ExposedSecret) (bool, error) {
+	key := es.Status.Key
+	binary := es.Status.Source == v1alpha1.SourceBinaryData
+	if _, ok := cm.Data[key]; ok && !binary {
+		return true, nil
+	}
+	if _, ok := cm.BinaryData[key]; ok && binary {
+		return true, nil
+	}
+
+	var secret corev1.Secret
+	if err := rc.cl.Get(rc.ctx, client.ObjectKey{Namespace: cm.Namespace, Name: secretName}, &secret); err != nil {
+		if errors.IsNotFound(err) {
+			rc.log.WarnContext(rc.ctx, "Can't revert remediation, Secret not found", "Secret", secretName, "key", key)
+			return false, nil
+		}
+		rc.log.ErrorContext(rc.ctx, "Failed to get Secret", "error", err)
+		return false, fmt.Errorf("failed to get Secret: %w", err)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		str, found := secret.StringData[key]
+		if !found {
+			rc.log.WarnContext(rc.ctx, "Can't revert remediation, key not found in Secret", "Secret", secretName, "key", key)
+			return false, nil
+		}
+		value = []byte(str)
+	}
+
+	if binary {
+		if cm.BinaryData == nil {
+			cm.BinaryData = map[string][]byte{}
+		}
+		cm.BinaryData[key] = value
+	} else {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key] = string(value)
+	}
+	rc.log.InfoContext(rc.ctx, "Restored key into ConfigMap", "Secret", secretName, "key", key)
+	return true, nil
+}
+
+// markReverted sets the ExposedSecret to [v1alpha1.ActionRevert] and moves it to [v1alpha1.PhaseReverted].
+func (rc *recCtx) markReverted(es *v1alpha1.ExposedSecret, message string) error {
+	if es.Spec.Action != v1alpha1.ActionRevert {
+		es.Spec.Action = v1alpha1.ActionRevert
+		status := es.Status
+		if err := rc.cl.Update(rc.ctx, es); err != nil {
+			rc.log.ErrorContext(rc.ctx, "Failed to update ExposedSecret", "error", err)
+			return fmt.Errorf("failed to update ExposedSecret: %w", err)
+		}
+		es.Status = status
+	}
+
+	from := es.Status.Phase
+	es.Status.Phase = v1alpha1.PhaseReverted
+	es.Status.Message = message
+	es.Status.CreatedSecretRef = nil
+	es.Status.LastUpdateTime = metav1.Now()
+	es.Status.SetConditions(es.Generation)
+	if err := rc.cl.Status().Update(rc.ctx, es); err != nil {
+		rc.log.ErrorContext(rc.ctx, "Failed to update ExposedSecret status", "error", err)
+		return fmt.Errorf("failed to update ExposedSecret status: %w", err)
+	}
+	rc.log.InfoContext(rc.ctx, "Reverted remediation", "ExposedSecret", es.Name)
+	rc.phaseChanged(es, from)
+	return nil
+}
+
+// deleteSecretOnRevert reports whether the Secret shared by the reverted ExposedSecrets should be deleted.
+// The Secret is kept if any workload was rewired to read from it.
+func deleteSecretOnRevert(reverted []*v1alpha1.ExposedSecret) bool {
+	del := false
+	for _, es := range reverted {
+		if len(es.Status.RewiredWorkloads) > 0 {
+			return false
+		}
+		del = del || es.Spec.DeleteSecretOnRevert
+	}
+	return del
+}
+
+// remediatedSecrets returns the sorted names of the Secrets still holding remediated keys of the ConfigMap.
+func remediatedSecrets(exposed []v1alpha1.ExposedSecret, reverted map[string][]*v1alpha1.ExposedSecret) []string {
+	var names []string
+	for i := range exposed {
+		ref := exposed[i].Status.CreatedSecretRef
+		if ref == nil || exposed[i].Status.Phase != v1alpha1.PhaseRemediated || reverted[ref.Name] != nil {
+			continue
+		}
+		names = append(names, ref.Name)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
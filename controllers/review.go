@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"github.com/lvlcn-t/secret-detection-operator/scanners"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Review is the result of scanning a [corev1.ConfigMap] on admission.
+type Review struct {
+	// Mode is the admission mode of the namespace's effective policy.
+	Mode v1alpha1.AdmissionMode
+	// Policy identifies the namespace's effective policy, see [v1alpha1.PolicySource].
+	Policy string
+	// Findings are the secrets the effective policy doesn't ignore.
+	Findings []ReviewFinding
+}
+
+// ReviewFinding is a secret found in a [corev1.ConfigMap] on admission.
+// It never holds the secret value itself.
+type ReviewFinding struct {
+	// Key is the ConfigMap key holding the secret.
+	Key string
+	// Location is the location of the secret, see [v1alpha1.ExposedSecretStatus.Location].
+	Location string
+	// RuleIDs are the IDs of the rules that detected the secret.
+	RuleIDs []string
+	// Severity is the highest severity of the secret's findings.
+	Severity scanners.Severity
+	// Action is the action the policy resolved for the secret.
+	Action v1alpha1.Action
+}
+
+// String returns a human-readable description of the finding.
+func (f *ReviewFinding) String() string {
+	return fmt.Sprintf("%s (rules: %v, severity: %s)", f.Location, f.RuleIDs, f.Severity)
+}
+
+// Review scans the ConfigMap with the effective policy of its namespace without modifying any resources.
+// It uses the same scanner and action resolution as the reconciliation, so secrets ignored by the policy,
+// excluded keys and ConfigMaps and user overrides on existing [v1alpha1.ExposedSecret] resources are respected.
+// If the policy's admission mode is [v1alpha1.AdmissionOff] or the ConfigMap isn't watched by the operator, it isn't scanned.
+func (r *ConfigMapReconciler) Review(ctx context.Context, cm *corev1.ConfigMap) (*Review, error) {
+	if watched, err := r.inScope(ctx, cm); err != nil || !watched {
+		return &Review{}, err
+	}
+
+	policy, err := r.resolveScanPolicy(ctx, cm.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve ScanPolicy: %w", err)
+	}
+
+	review := &Review{Mode: policy.Spec.AdmissionMode, Policy: policy.source}
+	if review.Mode == "" || review.Mode == v1alpha1.AdmissionOff {
+		return review, nil
+	}
+
+	rc := newRecCtx(r.Client, r.recorder, policy, cm)
+	rc.pepper = r.config.Pepper.Get()
+	rc.allowPlaintext = r.config.AllowPlaintext
+	if err = rc.initCtx(ctx); err != nil {
+		return nil, err
+	}
+	if rc.skipped() {
+		return review, nil
+	}
+	candidates, err := rc.findCandidates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan ConfigMap: %w", err)
+	}
+
+	for _, c := range candidates {
+		if rc.excluded(c) {
+			continue
+		}
+		_, res, err := rc.resolve(c)
+		if err != nil {
+			return nil, err
+		}
+		if res.Action == v1alpha1.ActionIgnore || res.Action == v1alpha1.ActionRevert {
+			continue
+		}
+		review.Findings = append(review.Findings, ReviewFinding{
+			Key:      c.key,
+			Location: c.location(),
+			RuleIDs:  c.ruleIDs(),
+			Severity: c.severity(),
+			Action:   res.Action,
+		})
+	}
+	return review, nil
+}
+
+// Remediate moves the secrets of the ConfigMap to Secrets before it is stored,
+// if the admission mode of the namespace's effective policy is [v1alpha1.AdmissionRemediate]
+// and the ConfigMap is watched by the operator. Otherwise, [v1alpha1.AnnotationRemediate] is removed from it.
+//
+// It takes the same path as the reconciliation's auto-remediation: a Secret is created for every
+// secret the policy doesn't ignore, its key is removed from the ConfigMap, the ConfigMap is annotated
+// with [v1alpha1.AnnotationExposedSecret] and the secret is recorded as a remediated [v1alpha1.ExposedSecret].
+// Secrets the policy only reports are remediated as well, unless the user overrode the action.
+//
+// The ConfigMap is only mutated in place if all secrets were remediated successfully.
+// In dry-run mode, the ConfigMap is mutated but no resources are created.
+// ConfigMaps using generateName are marked with [v1alpha1.AnnotationRemediate] instead, since the names
+// of the created resources are derived from the ConfigMap's name. They are remediated once they are stored.
+// The returned review lists the remediated secrets.
+func (r *ConfigMapReconciler) Remediate(ctx context.Context, cm *corev1.ConfigMap, dryRun bool) (*Review, error) {
+	if watched, err := r.inScope(ctx, cm); err != nil || !watched {
+		return &Review{}, err
+	}
+
+	policy, err := r.resolveScanPolicy(ctx, cm.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve ScanPolicy: %w", err)
+	}
+
+	review := &Review{Mode: policy.Spec.AdmissionMode, Policy: policy.source}
+	if review.Mode != v1alpha1.AdmissionRemediate {
+		// Only the policy decides whether ConfigMaps are remediated on admission.
+		delete(cm.Annotations, v1alpha1.AnnotationRemediate)
+		return review, nil
+	}
+
+	var cl client.Client = r.Client
+	rec := r.recorder
+	if dryRun {
+		// Nothing is persisted on dry-run, so there is nothing to emit Events about either.
+		cl = client.NewDryRunClient(r.Client)
+		rec = noopRecorder
+	}
+	rc := newRecCtx(cl, rec, policy, cm.DeepCopy())
+	rc.pepper = r.config.Pepper.Get()
+	rc.allowPlaintext = r.config.AllowPlaintext
+	if err = rc.initCtx(ctx); err != nil {
+		return nil, err
+	}
+	rc.admission = true
+	rc.inPlace = true
+	if rc.skipped() {
+		return review, nil
+	}
+	candidates, err := rc.findCandidates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan ConfigMap: %w", err)
+	}
+
+	for _, c := range candidates {
+		if rc.excluded(c) {
+			continue
+		}
+		f, err := rc.remediate(c, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			review.Findings = append(review.Findings, *f)
+		}
+	}
+
+	if cm.Name == "" && len(review.Findings) > 0 {
+		if rc.configMap.Annotations == nil {
+			rc.configMap.Annotations = map[string]string{}
+		}
+		rc.configMap.Annotations[v1alpha1.AnnotationRemediate] = "true"
+	}
+	*cm = *rc.configMap
+	return review, nil
+}
+
+// remediate processes a candidate on admission and returns it as a finding if its secret is remediated.
+// In dry-run mode, the key is only removed from the ConfigMap, since the Secret and ExposedSecret are
+// never persisted and can't be updated. ConfigMaps using generateName are left unchanged, see [ConfigMapReconciler.Remediate].
+func (rc *recCtx) remediate(c candidate, dryRun bool) (*ReviewFinding, error) {
+	_, res, err := rc.resolve(c)
+	if err != nil {
+		return nil, err
+	}
+	if res.Action != v1alpha1.ActionAutoRemediate {
+		return nil, nil
+	}
+
+	f := &ReviewFinding{
+		Key:      c.key,
+		Location: c.location(),
+		RuleIDs:  c.ruleIDs(),
+		Severity: c.severity(),
+		Action:   res.Action,
+	}
+	switch {
+	case rc.configMap.Name == "":
+		// We can't derive the names of the Secret and ExposedSecret
+		// before the API server generated the ConfigMap's name.
+	case dryRun:
+		if c.present(rc.configMap) {
+			stripKey(rc.configMap, v1alpha1.NewExposedSecretName(rc.configMap, c.key), c)
+		}
+	default:
+		if err = rc.process(c); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// inScope reports whether the ConfigMap is watched by the operator, see [config.Watch].
+func (r *ConfigMapReconciler) inScope(ctx context.Context, cm client.Object) (bool, error) {
+	w := &r.config.Watch
+	if !w.MatchesConfigMap(cm) {
+		return false, nil
+	}
+	if !w.HasNamespaceSelector() {
+		return true, nil
+	}
+
+	nsLabels, err := r.namespaceLabels(ctx, cm.GetNamespace())
+	if err != nil {
+		return false, fmt.Errorf("failed to get Namespace: %w", err)
+	}
+	return w.MatchesNamespace(nsLabels), nil
+}
+
+// scopePredicate drops the events of ConfigMaps the operator doesn't watch.
+// If the scope can't be determined, the event is passed on, so the reconciliation decides.
+func (r *ConfigMapReconciler) scopePredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		ok, err := r.inScope(context.Background(), obj)
+		return ok || err != nil
+	})
+}
+
+// mapNamespace enqueues all ConfigMaps in the namespace, since a label change may move them in or out of scope.
+func (r *ConfigMapReconciler) mapNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	if !r.config.Watch.WatchesNamespace(obj.GetName()) {
+		return nil
+	}
+	logr.FromContextAsSlogLogger(ctx).DebugContext(ctx, "Namespace labels changed, rescanning its ConfigMaps", "Namespace", obj.GetName())
+	return r.configMapRequests(ctx, client.InNamespace(obj.GetName()))
+}
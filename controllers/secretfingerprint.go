@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"github.com/lvlcn-t/secret-detection-operator/config"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ reconcile.Reconciler = (*SecretFingerprintReconciler)(nil)
+
+// +kubebuilder:rbac:groups=secretdetection.lvlcn-t.dev,resources=secretfingerprints,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=secretdetection.lvlcn-t.dev,resources=secretfingerprints/status,verbs=get;update;patch
+
+// IndexExposedSecretFingerprint is the field index of [v1alpha1.ExposedSecret] resources
+// by the fingerprint of the value they report, see [IndexExposedSecretByFingerprint].
+const IndexExposedSecretFingerprint = "status.fingerprint"
+
+// IndexExposedSecretByFingerprint returns the fingerprint of the value reported by the [v1alpha1.ExposedSecret].
+// It is used to index ExposedSecrets with [IndexExposedSecretFingerprint].
+func IndexExposedSecretByFingerprint(obj client.Object) []string {
+	es, ok := obj.(*v1alpha1.ExposedSecret)
+	if !ok || es.Status.Fingerprint == "" {
+		return nil
+	}
+	return []string{es.Status.Fingerprint}
+}
+
+// SecretFingerprintReconciler maintains a [v1alpha1.SecretFingerprint] for every fingerprint reported by
+// [v1alpha1.ExposedSecret] resources, listing all locations of the same secret value across the cluster.
+// A SecretFingerprint is deleted once no ExposedSecret reports its fingerprint anymore.
+type SecretFingerprintReconciler struct {
+	client.Client
+	config *config.Config
+}
+
+// NewSecretFingerprintReconciler creates a new [SecretFingerprintReconciler].
+func NewSecretFingerprintReconciler(c client.Client, cfg *config.Config) *SecretFingerprintReconciler {
+	return &SecretFingerprintReconciler{Client: c, config: cfg}
+}
+
+// Reconcile aggregates all [v1alpha1.ExposedSecret] resources reporting the fingerprint into its [v1alpha1.SecretFingerprint].
+func (r *SecretFingerprintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextAsSlogLogger(ctx).With("SecretFingerprint", req.Name)
+
+	var exposed v1alpha1.ExposedSecretList
+	if err := r.List(ctx, &exposed, client.MatchingFields{IndexExposedSecretFingerprint: req.Name}); err != nil {
+		log.ErrorContext(ctx, "Failed to list ExposedSecrets", "error", err)
+		return ctrl.Result{}, fmt.Errorf("failed to list ExposedSecrets: %w", err)
+	}
+
+	fp := &v1alpha1.SecretFingerprint{}
+	err := r.Get(ctx, req.NamespacedName, fp)
+	if err != nil && !errors.IsNotFound(err) {
+		log.ErrorContext(ctx, "Failed to get SecretFingerprint", "error", err)
+		return ctrl.Result{}, fmt.Errorf("failed to get SecretFingerprint: %w", err)
+	}
+	found := err == nil
+
+	if len(exposed.Items) == 0 {
+		if !found {
+			return ctrl.Result{}, nil
+		}
+		if err = r.Delete(ctx, fp); err != nil && !errors.IsNotFound(err) {
+			log.ErrorContext(ctx, "Failed to delete SecretFingerprint", "error", err)
+			return ctrl.Result{}, fmt.Errorf("failed to delete SecretFingerprint: %w", err)
+		}
+		log.InfoContext(ctx, "Deleted SecretFingerprint without locations")
+		return ctrl.Result{}, nil
+	}
+
+	status := r.aggregate(exposed.Items)
+	if found && equality.Semantic.DeepEqual(fp.Status.Locations, status.Locations) && fp.Status.KeyID == status.KeyID {
+		return ctrl.Result{}, nil
+	}
+
+	if !found {
+		fp = &v1alpha1.SecretFingerprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   req.Name,
+				Labels: map[string]string{v1alpha1.LabelManagedBy: v1alpha1.ManagedBy},
+			},
+		}
+		if err = r.Create(ctx, fp); err != nil {
+			log.ErrorContext(ctx, "Failed to create SecretFingerprint", "error", err)
+			return ctrl.Result{}, fmt.Errorf("failed to create SecretFingerprint: %w", err)
+		}
+	}
+
+	fp.Status = status
+	if err = r.Status().Update(ctx, fp); err != nil {
+		log.ErrorContext(ctx, "Failed to update SecretFingerprint status", "error", err)
+		return ctrl.Result{}, fmt.Errorf("failed to update SecretFingerprint status: %w", err)
+	}
+	log.DebugContext(ctx, "Updated SecretFingerprint", "locations", status.LocationCount, "exposed", status.ExposedCount)
+	return ctrl.Result{}, nil
+}
+
+// aggregate returns the status of the [v1alpha1.SecretFingerprint] reported by the ExposedSecrets.
+func (r *SecretFingerprintReconciler) aggregate(exposed []v1alpha1.ExposedSecret) v1alpha1.SecretFingerprintStatus {
+	status := v1alpha1.SecretFingerprintStatus{
+		Locations:      make([]v1alpha1.SecretLocation, 0, len(exposed)),
+		LastUpdateTime: metav1.Now(),
+	}
+	if pepper := r.config.Pepper.Get(); pepper != nil {
+		status.KeyID = pepper.ID
+	}
+
+	for i := range exposed {
+		es := &exposed[i]
+		status.Locations = append(status.Locations, v1alpha1.SecretLocation{
+			Namespace:     es.Namespace,
+			ConfigMap:     es.Status.ConfigMapReference.Name,
+			Location:      es.Status.Location(),
+			ExposedSecret: es.Name,
+			Phase:         es.Status.Phase,
+		})
+		if es.Status.Phase.Exposed() {
+			status.ExposedCount++
+		}
+	}
+	slices.SortFunc(status.Locations, func(a, b v1alpha1.SecretLocation) int {
+		return cmp.Or(
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.ConfigMap, b.ConfigMap),
+			cmp.Compare(a.Location, b.Location),
+			cmp.Compare(a.ExposedSecret, b.ExposedSecret),
+		)
+	})
+	status.LocationCount = len(status.Locations)
+	return status
+}
+
+// SetupWithManager registers this reconciler with the manager.
+func (r *SecretFingerprintReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.ExposedSecret{}, IndexExposedSecretFingerprint, IndexExposedSecretByFingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to index ExposedSecrets: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// Only deletions are of interest, so SecretFingerprints deleted by the user are recreated.
+		For(&v1alpha1.SecretFingerprint{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Updates enqueue the fingerprints of the old and the new object, so changed values leave their old aggregate.
+		Watches(&v1alpha1.ExposedSecret{}, handler.EnqueueRequestsFromMapFunc(mapExposedSecretFingerprint)).
+		Complete(r)
+}
+
+// mapExposedSecretFingerprint enqueues the [v1alpha1.SecretFingerprint] of the value reported by the [v1alpha1.ExposedSecret].
+func mapExposedSecretFingerprint(_ context.Context, obj client.Object) []reconcile.Request {
+	fingerprints := IndexExposedSecretByFingerprint(obj)
+	requests := make([]reconcile.Request, 0, len(fingerprints))
+	for _, fp := range fingerprints {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: fp}})
+	}
+	return requests
+}
@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+
+// workload is a resource running Pods from a Pod template.
+type workload struct {
+	// kind is the kind of the workload.
+	kind string
+	// obj is the workload resource.
+	obj client.Object
+	// spec is the Pod spec of the workload's Pod template.
+	spec *corev1.PodSpec
+	// immutable is true if the Pod template can't be changed after the workload was created.
+	immutable bool
+}
+
+// listWorkloads returns all workloads in the namespace of the ConfigMap.
+// Jobs created by a CronJob are not returned, because they are rewired through their CronJob.
+func (rc *recCtx) listWorkloads() ([]workload, error) {
+	opts := client.InNamespace(rc.configMap.Namespace)
+	var workloads []workload
+
+	var deployments appsv1.DeploymentList
+	if err := rc.cl.List(rc.ctx, &deployments, opts); err != nil {
+		return nil, fmt.Errorf("failed to list Deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		workloads = append(workloads, workload{kind: "Deployment", obj: d, spec: &d.Spec.Template.Spec})
+	}
+
+	var statefulSets appsv1.StatefulSetList
+	if err := rc.cl.List(rc.ctx, &statefulSets, opts); err != nil {
+		return nil, fmt.Errorf("failed to list StatefulSets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		workloads = append(workloads, workload{kind: "StatefulSet", obj: s, spec: &s.Spec.Template.Spec})
+	}
+
+	var daemonSets appsv1.DaemonSetList
+	if err := rc.cl.List(rc.ctx, &daemonSets, opts); err != nil {
+		return nil, fmt.Errorf("failed to list DaemonSets: %w", err)
+	}
+	for i := range daemonSets.Items {
+		d := &daemonSets.Items[i]
+		workloads = append(workloads, workload{kind: "DaemonSet", obj: d, spec: &d.Spec.Template.Spec})
+	}
+
+	var cronJobs batchv1.CronJobList
+	if err := rc.cl.List(rc.ctx, &cronJobs, opts); err != nil {
+		return nil, fmt.Errorf("failed to list CronJobs: %w", err)
+	}
+	for i := range cronJobs.Items {
+		c := &cronJobs.Items[i]
+		workloads = append(workloads, workload{kind: "CronJob", obj: c, spec: &c.Spec.JobTemplate.Spec.Template.Spec})
+	}
+
+	var jobs batchv1.JobList
+	if err := rc.cl.List(rc.ctx, &jobs, opts); err != nil {
+		return nil, fmt.Errorf("failed to list Jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		j := &jobs.Items[i]
+		if owner := metav1.GetControllerOf(j); owner != nil && owner.Kind == "CronJob" {
+			continue
+		}
+		workloads = append(workloads, workload{kind: "Job", obj: j, spec: &j.Spec.Template.Spec, immutable: true})
+	}
+
+	return workloads, nil
+}
+
+// rewireWorkloads patches all workloads reading the candidate's key from the ConfigMap
+// to read it from the given Secret instead. The rewired workloads are remembered per key,
+// so every candidate of the key reports them.
+func (rc *recCtx) rewireWorkloads(secret *corev1.Secret, c candidate) error {
+	if _, ok := rc.rewired[c.key]; ok {
+		return nil
+	}
+
+	workloads, err := rc.listWorkloads()
+	if err != nil {
+		return err
+	}
+
+	var refs []v1alpha1.WorkloadReference
+	for _, w := range workloads {
+		if !rewirePodSpec(w.spec, rc.configMap.Name, c.key, secret.Name) {
+			continue
+		}
+		if w.immutable {
+			rc.log.WarnContext(rc.ctx, "Workload reads the remediated key but can't be rewired, because its Pod template is immutable",
+				"kind", w.kind, "name", w.obj.GetName(), "key", c.key)
+			continue
+		}
+		if err = rc.cl.Update(rc.ctx, w.obj); err != nil {
+			return fmt.Errorf("failed to update %s %q: %w", w.kind, w.obj.GetName(), err)
+		}
+		rc.log.InfoContext(rc.ctx, "Rewired workload to the remediated Secret", "kind", w.kind, "name", w.obj.GetName(), "key", c.key)
+		WorkloadsRewired.WithLabelValues(rc.configMap.Namespace, w.kind).Inc()
+		refs = append(refs, v1alpha1.WorkloadReference{Kind: w.kind, Name: w.obj.GetName()})
+	}
+
+	if rc.rewired == nil {
+		rc.rewired = map[string][]v1alpha1.WorkloadReference{}
+	}
+	rc.rewired[c.key] = refs
+	return nil
+}
+
+// rewirePodSpec patches the Pod spec to read the given ConfigMap key from the Secret holding it:
+//
+//   - env entries with a configMapKeyRef to the key are replaced by a secretKeyRef,
+//   - envFrom sources importing the whole ConfigMap are complemented by the Secret,
+//   - ConfigMap volumes projecting the key are turned into projected volumes including the Secret.
+//
+// It reports whether the Pod spec was changed. Already rewired Pod specs are left untouched.
+func rewirePodSpec(spec *corev1.PodSpec, configMap, key, secret string) bool {
+	changed := false
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			changed = rewireContainer(&containers[i], configMap, key, secret) || changed
+		}
+	}
+	for i := range spec.Volumes {
+		changed = rewireVolume(&spec.Volumes[i], configMap, key, secret) || changed
+	}
+	return changed
+}
+
+// rewireContainer patches the environment of the container, see [rewirePodSpec].
+func rewireContainer(ctr *corev1.Container, configMap, key, secret string) bool {
+	changed := false
+	for i := range ctr.Env {
+		from := ctr.Env[i].ValueFrom
+		if from == nil || from.ConfigMapKeyRef == nil || from.ConfigMapKeyRef.Name != configMap || from.ConfigMapKeyRef.Key != key {
+			continue
+		}
+		ctr.Env[i].ValueFrom = &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+			Key:                  key,
+			Optional:             from.ConfigMapKeyRef.Optional,
+		}}
+		changed = true
+	}
+
+	for i := 0; i < len(ctr.EnvFrom); i++ {
+		ref := ctr.EnvFrom[i].ConfigMapRef
+		if ref == nil || ref.Name != configMap {
+			continue
+		}
+		if slices.ContainsFunc(ctr.EnvFrom, func(e corev1.EnvFromSource) bool {
+			return e.SecretRef != nil && e.SecretRef.Name == secret && e.Prefix == ctr.EnvFrom[i].Prefix
+		}) {
+			continue
+		}
+		// The Secret is imported right after the ConfigMap, so it takes precedence over it like the ConfigMap key did.
+		ctr.EnvFrom = slices.Insert(ctr.EnvFrom, i+1, corev1.EnvFromSource{
+			Prefix:    ctr.EnvFrom[i].Prefix,
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secret}, Optional: ref.Optional},
+		})
+		changed = true
+		i++
+	}
+	return changed
+}
+
+// rewireVolume patches the volume if it projects the ConfigMap key, see [rewirePodSpec].
+func rewireVolume(vol *corev1.Volume, configMap, key, secret string) bool {
+	if cm := vol.ConfigMap; cm != nil && cm.Name == configMap {
+		projected := &corev1.ProjectedVolumeSource{
+			Sources: []corev1.VolumeProjection{{ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: cm.LocalObjectReference,
+				Items:                cm.Items,
+				Optional:             cm.Optional,
+			}}},
+			DefaultMode: cm.DefaultMode,
+		}
+		if !rewireProjection(projected, configMap, key, secret) {
+			return false
+		}
+		vol.VolumeSource = corev1.VolumeSource{Projected: projected}
+		return true
+	}
+
+	if vol.Projected != nil {
+		return rewireProjection(vol.Projected, configMap, key, secret)
+	}
+	return false
+}
+
+// rewireProjection moves the ConfigMap key of the projected volume to a projection of the Secret.
+func rewireProjection(projected *corev1.ProjectedVolumeSource, configMap, key, secret string) bool {
+	if slices.ContainsFunc(projected.Sources, func(p corev1.VolumeProjection) bool {
+		return p.Secret != nil && p.Secret.Name == secret
+	}) {
+		return false
+	}
+
+	var items []corev1.KeyToPath
+	var optional *bool
+	sources := make([]corev1.VolumeProjection, 0, len(projected.Sources)+1)
+	for _, src := range projected.Sources {
+		cm := src.ConfigMap
+		if cm == nil || cm.Name != configMap {
+			sources = append(sources, src)
+			continue
+		}
+
+		if len(cm.Items) == 0 {
+			// The whole ConfigMap is projected, so the key was projected to a file named after it.
+			items = append(items, corev1.KeyToPath{Key: key, Path: key})
+			optional = cm.Optional
+			sources = append(sources, src)
+			continue
+		}
+
+		remaining := slices.DeleteFunc(slices.Clone(cm.Items), func(item corev1.KeyToPath) bool {
+			if item.Key != key {
+				return false
+			}
+			items = append(items, item)
+			optional = cm.Optional
+			return true
+		})
+		// An empty list of items would project the whole ConfigMap, so the source is dropped instead.
+		if len(remaining) > 0 {
+			cm = cm.DeepCopy()
+			cm.Items = remaining
+			sources = append(sources, corev1.VolumeProjection{ConfigMap: cm})
+		}
+	}
+	if len(items) == 0 {
+		return false
+	}
+
+	projected.Sources = append(sources, corev1.VolumeProjection{Secret: &corev1.SecretProjection{
+		LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+		Items:                items,
+		Optional:             optional,
+	}})
+	return true
+}
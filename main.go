@@ -7,6 +7,8 @@ import (
 	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
 	"github.com/lvlcn-t/secret-detection-operator/config"
 	"github.com/lvlcn-t/secret-detection-operator/controllers"
+	"github.com/lvlcn-t/secret-detection-operator/scanners/factory"
+	"github.com/lvlcn-t/secret-detection-operator/webhooks"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -15,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var scheme = runtime.NewScheme()
@@ -28,15 +31,10 @@ func init() { //nolint:gochecknoinits // Common pattern for controller-runtime
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 }
 
-const (
-	metricsAddr    = ":9090"
-	healthAddr     = ":8080"
-	leaderElection = false
-)
-
 func main() {
 	var configPath string
 	flag.StringVar(&configPath, "config", "", "Path to the configuration file")
+	flags := config.BindFlags(flag.CommandLine)
 
 	opts := zap.Options{
 		Level: zapcore.DebugLevel,
@@ -52,14 +50,29 @@ func main() {
 		setupLog.Error(err, "Unable to load configuration")
 		os.Exit(1)
 	}
+	if err = flags.Apply(cfg); err != nil {
+		setupLog.Error(err, "Invalid flags")
+		os.Exit(1)
+	}
 	setupLog.Info("Loaded configuration", "config", cfg)
+	factory.SetCacheSize(cfg.ScannerCacheSize)
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                server.Options{BindAddress: metricsAddr},
-		HealthProbeBindAddress: healthAddr,
-		LeaderElection:         leaderElection,
-		LeaderElectionID:       config.AppURL,
+		Scheme:                        scheme,
+		Metrics:                       server.Options{BindAddress: cfg.Manager.MetricsAddr},
+		HealthProbeBindAddress:        cfg.Manager.HealthAddr,
+		LeaderElection:                cfg.Manager.LeaderElection.Enabled,
+		LeaderElectionID:              config.AppURL,
+		LeaderElectionNamespace:       cfg.Manager.LeaderElection.Namespace,
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 &cfg.Manager.LeaderElection.LeaseDuration,
+		RenewDeadline:                 &cfg.Manager.LeaderElection.RenewDeadline,
+		RetryPeriod:                   &cfg.Manager.LeaderElection.RetryPeriod,
+		Cache:                         cfg.CacheOptions(),
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    cfg.Webhook.Port,
+			CertDir: cfg.Webhook.CertDir,
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "Unable to start manager")
@@ -72,13 +85,46 @@ func main() {
 		os.Exit(1)
 	}
 	setupLog.Info("Configuration is valid")
+	if err = cfg.Pepper.Load(ctx, mgr.GetAPIReader()); err != nil {
+		setupLog.Error(err, "Unable to load pepper")
+		os.Exit(1)
+	}
 
-	controller := controllers.NewConfigMapReconciler(mgr.GetClient(), mgr.GetScheme(), cfg)
+	controller := controllers.NewConfigMapReconciler(mgr.GetClient(), mgr.GetScheme(), cfg, mgr.GetEventRecorder(config.AppName))
 	if err = controller.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Unable to create controller", "controller", "ConfigMap")
 		os.Exit(1)
 	}
 
+	// Fingerprints are keyed by the pepper and aggregated by cluster-scoped resources.
+	if cfg.Pepper.Enabled() && !cfg.Watch.Namespaced {
+		if err = controllers.NewSecretFingerprintReconciler(mgr.GetClient(), cfg).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "Unable to create controller", "controller", "SecretFingerprint")
+			os.Exit(1)
+		}
+	}
+
+	if cfg.Webhook.Enabled {
+		validator := webhooks.NewScanPolicyValidator(mgr.GetAPIReader(), cfg.Webhook.SinglePolicyPerNamespace, cfg.Pepper.Enabled())
+		if err = validator.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "Unable to create webhook", "webhook", "ScanPolicy")
+			os.Exit(1)
+		}
+		// Admit the operator's own writes, so reverting a remediation isn't denied under the Enforce admission mode.
+		var operator string
+		if operator, err = webhooks.Username(ctx, mgr.GetClient()); err != nil {
+			setupLog.Error(err, "Unable to determine own username, the ConfigMap webhooks review all writes")
+		}
+		if err = webhooks.NewConfigMapValidator(controller, operator).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "Unable to create webhook", "webhook", "ConfigMap")
+			os.Exit(1)
+		}
+		if err = webhooks.NewConfigMapRemediator(controller, operator).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "Unable to create webhook", "webhook", "ConfigMap")
+			os.Exit(1)
+		}
+	}
+
 	if err = mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "Unable to set up health check")
 		os.Exit(1)
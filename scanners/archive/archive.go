@@ -0,0 +1,208 @@
+// Package archive decodes the binary payloads stored in a ConfigMap's binaryData
+// so that the files inside of common container formats can be scanned for secrets.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+)
+
+// Format represents the container format a binary payload was recognized as.
+type Format string
+
+// String returns the string representation of the format.
+func (f Format) String() string {
+	return string(f)
+}
+
+const (
+	// FormatRaw is used for payloads that are not a known container format.
+	FormatRaw Format = "raw"
+	// FormatGzip is used for gzip compressed payloads.
+	FormatGzip Format = "gzip"
+	// FormatTar is used for tar archives.
+	FormatTar Format = "tar"
+	// FormatZip is used for zip archives.
+	FormatZip Format = "zip"
+	// FormatPKCS12 is used for PKCS#12 keystores (.p12, .pfx).
+	FormatPKCS12 Format = "pkcs12"
+	// FormatJKS is used for Java keystores (JKS and JCEKS).
+	FormatJKS Format = "jks"
+)
+
+// IsKeystore reports whether the format is an (encrypted) keystore.
+// Keystores can't be opened without their password, so their presence
+// in a ConfigMap is treated as an exposed secret on its own.
+func (f Format) IsKeystore() bool {
+	return f == FormatPKCS12 || f == FormatJKS
+}
+
+// Limits protect the operator from decompression bombs and oversized payloads.
+const (
+	// maxDepth is the maximum nesting level of containers (e.g. zip in tar in gzip).
+	maxDepth = 4
+	// maxEntries is the maximum number of files that are extracted from a single payload.
+	maxEntries = 1000
+	// maxTotalSize is the maximum number of decompressed bytes extracted from a single payload.
+	maxTotalSize = 32 << 20
+)
+
+// ErrLimitExceeded is returned if a payload exceeds the extraction limits.
+var ErrLimitExceeded = errors.New("archive exceeds extraction limits")
+
+// Entry is a single file extracted from a binary payload.
+type Entry struct {
+	// Path is the path of the file inside the container.
+	// It is empty if the payload itself is not a container.
+	Path string
+	// Format is the format the entry was recognized as.
+	Format Format
+	// Data is the decoded content of the entry.
+	Data []byte
+}
+
+// Extract decodes the given payload and returns all files found inside of it.
+// Nested containers are extracted recursively; their paths are joined with a '!',
+// e.g. "app.tar.gz!config/application.yaml".
+// Payloads that aren't a known container are returned as a single [FormatRaw] entry.
+func Extract(data []byte) ([]Entry, error) {
+	e := &extractor{}
+	if err := e.extract("", data, 0); err != nil {
+		return nil, err
+	}
+	return e.entries, nil
+}
+
+// extractor keeps track of the extraction limits while walking nested containers.
+type extractor struct {
+	entries []Entry
+	size    int64
+}
+
+func (e *extractor) extract(name string, data []byte, depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("%w: nesting deeper than %d levels", ErrLimitExceeded, maxDepth)
+	}
+
+	format := Detect(data)
+	switch format {
+	case FormatGzip:
+		return e.extractGzip(name, data, depth)
+	case FormatTar:
+		return e.extractTar(name, data, depth)
+	case FormatZip:
+		return e.extractZip(name, data, depth)
+	default:
+		return e.add(Entry{Path: name, Format: format, Data: data})
+	}
+}
+
+func (e *extractor) extractGzip(name string, data []byte, depth int) error {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to open gzip stream %q: %w", name, err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	content, err := e.read(zr)
+	if err != nil {
+		return fmt.Errorf("failed to decompress gzip stream %q: %w", name, err)
+	}
+	// A gzip stream only holds a single file, so we don't add a path segment
+	// unless the header tells us the original file name.
+	return e.extract(join(name, zr.Name), content, depth+1)
+}
+
+func (e *extractor) extractTar(name string, data []byte, depth int) error {
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive %q: %w", name, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := e.read(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read %q from tar archive %q: %w", hdr.Name, name, err)
+		}
+		if err = e.extract(join(name, hdr.Name), content, depth+1); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *extractor) extractZip(name string, data []byte, depth int) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("failed to open zip archive %q: %w", name, err)
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		content, err := e.readZipFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to read %q from zip archive %q: %w", f.Name, name, err)
+		}
+		if err = e.extract(join(name, f.Name), content, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *extractor) readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	return e.read(rc)
+}
+
+// read reads from r until EOF while enforcing the total size limit.
+func (e *extractor) read(r io.Reader) ([]byte, error) {
+	remaining := maxTotalSize - e.size
+	content, err := io.ReadAll(io.LimitReader(r, remaining+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > remaining {
+		return nil, fmt.Errorf("%w: more than %d decompressed bytes", ErrLimitExceeded, maxTotalSize)
+	}
+	e.size += int64(len(content))
+	return content, nil
+}
+
+func (e *extractor) add(entry Entry) error {
+	if len(e.entries) >= maxEntries {
+		return fmt.Errorf("%w: more than %d files", ErrLimitExceeded, maxEntries)
+	}
+	e.entries = append(e.entries, entry)
+	return nil
+}
+
+// join appends the path of a file inside a container to the path of the container.
+func join(container, name string) string {
+	name = path.Clean("/" + name)[1:]
+	if container == "" {
+		return name
+	}
+	if name == "" {
+		return container
+	}
+	return container + "!" + name
+}
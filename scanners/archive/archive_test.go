@@ -0,0 +1,267 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// file is a file of a test archive.
+type file struct {
+	name string
+	data []byte
+}
+
+func newTar(t *testing.T, files ...file) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	for _, f := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: f.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(f.data))}))
+		_, err := tw.Write(f.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func newZip(t *testing.T, files ...file) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_, err := zw.Create("dir/")
+	require.NoError(t, err)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		require.NoError(t, err)
+		_, err = w.Write(f.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func newGzip(t *testing.T, name string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Name = name
+	_, err := zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// pkcs12Header is the start of a DER encoded PFX structure with a pkcs7-data auth safe.
+var pkcs12Header = []byte{
+	0x30, 0x82, 0x01, 0x00, // SEQUENCE
+	0x02, 0x01, 0x03, // version 3
+	0x30, 0x82, 0x00, 0xf0, // SEQUENCE (ContentInfo)
+	0x06, 0x09, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x01, 0x07, 0x01, // pkcs7-data
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Format
+	}{
+		{name: "empty", data: nil, want: FormatRaw},
+		{name: "text", data: []byte("password=hunter2"), want: FormatRaw},
+		{name: "gzip", data: newGzip(t, "", []byte("a")), want: FormatGzip},
+		{name: "zip", data: newZip(t, file{name: "a", data: []byte("a")}), want: FormatZip},
+		{name: "empty zip", data: newZip(t), want: FormatZip},
+		{name: "tar", data: newTar(t, file{name: "a", data: []byte("a")}), want: FormatTar},
+		{name: "short tar header", data: []byte("ustar"), want: FormatRaw},
+		{name: "jks", data: []byte{0xfe, 0xed, 0xfe, 0xed, 0, 0, 0, 2}, want: FormatJKS},
+		{name: "jceks", data: []byte{0xce, 0xce, 0xce, 0xce, 0, 0, 0, 2}, want: FormatJKS},
+		{name: "pkcs12", data: pkcs12Header, want: FormatPKCS12},
+		{name: "der sequence without pfx version", data: []byte{0x30, 0x03, 0x02, 0x01, 0x01}, want: FormatRaw},
+		{name: "pfx version without pkcs7 data", data: []byte{0x30, 0x82, 0x01, 0x00, 0x02, 0x01, 0x03, 0x30, 0x00}, want: FormatRaw},
+		{name: "pkcs7 data beyond header", data: append(append([]byte{0x30, 0x02, 0x01, 0x03}, make([]byte, 32)...), pkcs7DataOID...), want: FormatRaw},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Detect(tt.data))
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	secret := []byte("password=hunter2")
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []Entry
+		wantErr error
+	}{
+		{
+			name: "raw payload",
+			data: secret,
+			want: []Entry{{Path: "", Format: FormatRaw, Data: secret}},
+		},
+		{
+			name: "gzip without name",
+			data: newGzip(t, "", secret),
+			want: []Entry{{Path: "", Format: FormatRaw, Data: secret}},
+		},
+		{
+			name: "gzip with name",
+			data: newGzip(t, "app.properties", secret),
+			want: []Entry{{Path: "app.properties", Format: FormatRaw, Data: secret}},
+		},
+		{
+			name: "tar skips directories",
+			data: newTar(t, file{name: "config/app.properties", data: secret}, file{name: "README.md", data: []byte("hi")}),
+			want: []Entry{
+				{Path: "config/app.properties", Format: FormatRaw, Data: secret},
+				{Path: "README.md", Format: FormatRaw, Data: []byte("hi")},
+			},
+		},
+		{
+			name: "zip skips directories",
+			data: newZip(t, file{name: "config/app.properties", data: secret}),
+			want: []Entry{{Path: "config/app.properties", Format: FormatRaw, Data: secret}},
+		},
+		{
+			name: "nested archives",
+			data: newGzip(t, "", newTar(t, file{name: "bundle.zip", data: newZip(t, file{name: "app.properties", data: secret})})),
+			want: []Entry{{Path: "bundle.zip!app.properties", Format: FormatRaw, Data: secret}},
+		},
+		{
+			name: "keystores are not extracted",
+			data: newZip(t, file{name: "store.jks", data: []byte{0xfe, 0xed, 0xfe, 0xed}}),
+			want: []Entry{{Path: "store.jks", Format: FormatJKS, Data: []byte{0xfe, 0xed, 0xfe, 0xed}}},
+		},
+		{
+			name: "path traversal is cleaned",
+			data: newTar(t, file{name: "../../etc/passwd", data: secret}),
+			want: []Entry{{Path: "etc/passwd", Format: FormatRaw, Data: secret}},
+		},
+		{
+			name:    "nesting deeper than the limit",
+			data:    nest(t, maxDepth+1, secret),
+			wantErr: ErrLimitExceeded,
+		},
+		{
+			name: "nesting at the limit",
+			data: nest(t, maxDepth, secret),
+			want: []Entry{{Path: "", Format: FormatRaw, Data: secret}},
+		},
+		{
+			name:    "more entries than the limit",
+			data:    newTar(t, files(maxEntries+1)...),
+			wantErr: ErrLimitExceeded,
+		},
+		{
+			name:    "decompression bomb",
+			data:    newGzip(t, "", make([]byte, maxTotalSize+1)),
+			wantErr: ErrLimitExceeded,
+		},
+		{
+			name:    "decompressed size is summed across entries",
+			data:    newZip(t, file{name: "a", data: make([]byte, maxTotalSize/2+1)}, file{name: "b", data: make([]byte, maxTotalSize/2+1)}),
+			wantErr: ErrLimitExceeded,
+		},
+		{
+			name:    "truncated gzip",
+			data:    newGzip(t, "", secret)[:12],
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(tt.data)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// nest wraps the data in the given number of gzip streams.
+func nest(t *testing.T, depth int, data []byte) []byte {
+	t.Helper()
+	for range depth {
+		data = newGzip(t, "", data)
+	}
+	return data
+}
+
+// files returns n small files.
+func files(n int) []file {
+	fs := make([]file, 0, n)
+	for i := range n {
+		fs = append(fs, file{name: fmt.Sprintf("f%d", i), data: []byte("x")})
+	}
+	return fs
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		name      string
+		container string
+		file      string
+		want      string
+	}{
+		{name: "top level", container: "", file: "config/app.yaml", want: "config/app.yaml"},
+		{name: "nested", container: "bundle.tar.gz", file: "config/app.yaml", want: "bundle.tar.gz!config/app.yaml"},
+		{name: "unnamed file", container: "bundle.tar.gz", file: "", want: "bundle.tar.gz"},
+		{name: "unnamed top level", container: "", file: "", want: ""},
+		{name: "leading slash", container: "", file: "/etc/app.yaml", want: "etc/app.yaml"},
+		{name: "parent directories", container: "a.zip", file: "../../b/./c", want: "a.zip!b/c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, join(tt.container, tt.file))
+		})
+	}
+}
+
+func TestHasPrivateKey(t *testing.T) {
+	jks := func(version byte, entries ...byte) []byte {
+		return append([]byte{0xfe, 0xed, 0xfe, 0xed, 0, 0, 0, version}, entries...)
+	}
+	trustedCert := []byte{0, 0, 0, 2, 0, 2, 'c', 'a', 0, 0, 0, 0, 0, 0, 0, 0, 0, 5, 'X', '.', '5', '0', '9', 0, 0, 0, 2, 0xaa, 0xbb}
+	privateKey := []byte{0, 0, 0, 1, 0, 3, 'k', 'e', 'y', 0, 0, 0, 0, 0, 0, 0, 0}
+
+	tests := []struct {
+		name   string
+		format Format
+		data   []byte
+		want   bool
+	}{
+		{name: "jks with private key", format: FormatJKS, data: jks(2, append([]byte{0, 0, 0, 1}, privateKey...)...), want: true},
+		{name: "jks with private key after certificate", format: FormatJKS, data: jks(2, append(append([]byte{0, 0, 0, 2}, trustedCert...), privateKey...)...), want: true},
+		{name: "jceks with secret key", format: FormatJKS, data: jks(2, 0, 0, 0, 1, 0, 0, 0, 3, 0, 1, 'k', 0, 0, 0, 0, 0, 0, 0, 0), want: true},
+		{name: "jks truststore", format: FormatJKS, data: jks(2, append([]byte{0, 0, 0, 1}, trustedCert...)...), want: false},
+		{name: "jks version 1 truststore", format: FormatJKS, data: jks(1, 0, 0, 0, 1, 0, 0, 0, 2, 0, 2, 'c', 'a', 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0xaa), want: false},
+		{name: "empty jks", format: FormatJKS, data: jks(2, 0, 0, 0, 0), want: false},
+		{name: "truncated jks", format: FormatJKS, data: jks(2, 0, 0, 0, 2, 0, 0, 0, 2, 0, 2, 'c'), want: true},
+		{name: "jks with unknown entry", format: FormatJKS, data: jks(2, 0, 0, 0, 1, 0, 0, 0, 9, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0), want: true},
+		{name: "pkcs12 with shrouded key", format: FormatPKCS12, data: append(bytes.Clone(pkcs12Header), pkcs12ShroudedKeyBagOID...), want: true},
+		{name: "pkcs12 with key", format: FormatPKCS12, data: append(bytes.Clone(pkcs12Header), pkcs12KeyBagOID...), want: true},
+		{name: "pkcs12 truststore", format: FormatPKCS12, data: pkcs12Header, want: false},
+		{name: "no keystore", format: FormatRaw, data: privateKey, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, HasPrivateKey(tt.format, tt.data))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package archive
+
+import (
+	"bytes"
+)
+
+var (
+	// gzipMagic is the header of a gzip stream (RFC 1952).
+	gzipMagic = []byte{0x1f, 0x8b}
+	// zipMagic is the header of a zip local file entry.
+	zipMagic = []byte("PK\x03\x04")
+	// zipEmptyMagic is the header of an empty zip archive (end of central directory only).
+	zipEmptyMagic = []byte("PK\x05\x06")
+	// tarMagic is the "ustar" magic located at offset 257 of a POSIX or GNU tar header.
+	tarMagic = []byte("ustar")
+	// jksMagic is the header of a Java keystore.
+	jksMagic = []byte{0xfe, 0xed, 0xfe, 0xed}
+	// jceksMagic is the header of a Java keystore using the JCEKS format.
+	jceksMagic = []byte{0xce, 0xce, 0xce, 0xce}
+	// pkcs12Version is the DER encoded version field (INTEGER 3) of a PFX structure.
+	pkcs12Version = []byte{0x02, 0x01, 0x03}
+	// pkcs7DataOID is the DER encoded OID 1.2.840.113549.1.7.1 (pkcs7-data)
+	// used as the content type of the PFX auth safe.
+	pkcs7DataOID = []byte{0x06, 0x09, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x01, 0x07, 0x01}
+)
+
+const (
+	// tarMagicOffset is the offset of the magic inside a tar header.
+	tarMagicOffset = 257
+	// pkcs12HeaderSize is the number of leading bytes searched for the PFX markers.
+	pkcs12HeaderSize = 32
+	// derSequence is the DER tag of a constructed SEQUENCE.
+	derSequence = 0x30
+)
+
+// Detect returns the container format of the given payload based on its magic bytes.
+// It returns [FormatRaw] if the format is not recognized.
+func Detect(data []byte) Format {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		return FormatGzip
+	case bytes.HasPrefix(data, zipMagic), bytes.HasPrefix(data, zipEmptyMagic):
+		return FormatZip
+	case len(data) >= tarMagicOffset+len(tarMagic) && bytes.Equal(data[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic):
+		return FormatTar
+	case bytes.HasPrefix(data, jksMagic), bytes.HasPrefix(data, jceksMagic):
+		return FormatJKS
+	case isPKCS12(data):
+		return FormatPKCS12
+	default:
+		return FormatRaw
+	}
+}
+
+// isPKCS12 reports whether the payload looks like a DER encoded PFX structure (RFC 7292):
+//
+//	PFX ::= SEQUENCE { version INTEGER {v3(3)}, authSafe ContentInfo, macData MacData OPTIONAL }
+func isPKCS12(data []byte) bool {
+	if len(data) < 2 || data[0] != derSequence {
+		return false
+	}
+	header := data[:min(len(data), pkcs12HeaderSize)]
+	version := bytes.Index(header, pkcs12Version)
+	if version < 0 {
+		return false
+	}
+	return bytes.Contains(header[version:], pkcs7DataOID)
+}
@@ -0,0 +1,120 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	// pkcs12KeyBagOID is the DER encoded OID 1.2.840.113549.1.12.10.1.1 (keyBag) of an unencrypted private key.
+	pkcs12KeyBagOID = []byte{0x06, 0x0b, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x01, 0x0c, 0x0a, 0x01, 0x01}
+	// pkcs12ShroudedKeyBagOID is the DER encoded OID 1.2.840.113549.1.12.10.1.2 (pkcs8ShroudedKeyBag) of an encrypted private key.
+	pkcs12ShroudedKeyBagOID = []byte{0x06, 0x0b, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x01, 0x0c, 0x0a, 0x01, 0x02}
+)
+
+// Tags of the entries of a Java keystore.
+const (
+	jksPrivateKeyEntry  = 1
+	jksTrustedCertEntry = 2
+	jksSecretKeyEntry   = 3
+)
+
+// errTruncated is returned if a keystore ends in the middle of an entry.
+var errTruncated = errors.New("truncated keystore")
+
+// HasPrivateKey reports whether the keystore holds a private or secret key,
+// as opposed to a truststore only holding certificates.
+//
+// The entries of a keystore can be listed without its password:
+//   - Java keystores store the type of every entry in plaintext. Keystores that can't be
+//     parsed are assumed to hold a key, so a malformed keystore can't hide one.
+//   - PKCS#12 keystores created by common tools (e.g. openssl and keytool) store their keys in
+//     shrouded key bags, whose type is visible even if the certificates are encrypted.
+//
+// It returns false for formats that aren't keystores.
+func HasPrivateKey(f Format, data []byte) bool {
+	switch f {
+	case FormatJKS:
+		hasKey, err := jksHasPrivateKey(data)
+		return hasKey || err != nil
+	case FormatPKCS12:
+		return bytes.Contains(data, pkcs12ShroudedKeyBagOID) || bytes.Contains(data, pkcs12KeyBagOID)
+	default:
+		return false
+	}
+}
+
+// jksHasPrivateKey walks the entries of a Java keystore (JKS or JCEKS) until it finds a key.
+//
+//	magic u32 | version u32 | count u32 | entries...
+//	entry: tag u32 | alias UTF | timestamp u64 | content
+//	trusted certificate content: type UTF (version 2 only) | length u32 | certificate
+func jksHasPrivateKey(data []byte) (bool, error) {
+	r := &jksReader{data: data}
+	r.skip(4)
+	version := r.uint32()
+	count := r.uint32()
+	if r.err != nil {
+		return false, r.err
+	}
+
+	for range count {
+		tag := r.uint32()
+		r.skip(int(r.uint16()))
+		r.skip(8)
+		if r.err != nil {
+			return false, r.err
+		}
+
+		switch tag {
+		case jksPrivateKeyEntry, jksSecretKeyEntry:
+			return true, nil
+		case jksTrustedCertEntry:
+			if version == 2 {
+				r.skip(int(r.uint16()))
+			}
+			r.skip(int(r.uint32()))
+		default:
+			return false, errors.New("unknown keystore entry")
+		}
+	}
+	return false, r.err
+}
+
+// jksReader reads the big endian fields of a Java keystore and remembers the first error.
+type jksReader struct {
+	data []byte
+	err  error
+}
+
+func (r *jksReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data) {
+		r.err = errTruncated
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *jksReader) skip(n int) {
+	r.next(n)
+}
+
+func (r *jksReader) uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *jksReader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
@@ -0,0 +1,126 @@
+package factory
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lvlcn-t/secret-detection-operator/scanners"
+)
+
+// DefaultCacheSize is the default number of configured scanners kept by the cache.
+const DefaultCacheSize = 64
+
+// cache is a bounded least recently used cache of configured scanners.
+// Scanners are keyed by the hash of their configuration, so updating a policy's configuration
+// invalidates its scanner: the new configuration misses the cache and the old scanner is evicted eventually.
+type cache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	// lru orders the entries from the most to the least recently used one.
+	lru *list.List
+}
+
+// entry is a cached scanner.
+type entry struct {
+	key     string
+	scanner scanners.Scanner
+}
+
+// scannerCache caches the scanners created by [Get].
+var scannerCache = newCache(DefaultCacheSize)
+
+func newCache(size int) *cache {
+	return &cache{size: size, entries: map[string]*list.Element{}, lru: list.New()}
+}
+
+// SetCacheSize bounds the number of configured scanners kept by [Get] and evicts the least recently used ones.
+// Scanners with custom configurations aren't cached if the size isn't positive.
+func SetCacheSize(size int) {
+	scannerCache.mu.Lock()
+	defer scannerCache.mu.Unlock()
+	scannerCache.size = size
+	scannerCache.evict()
+	ScannerCacheEntries.Set(float64(scannerCache.lru.Len()))
+}
+
+// get returns the cached scanner for the configuration or builds and caches a new one.
+// The lock isn't held while building, so a scanner might be built concurrently; the last one built wins.
+func (c *cache) get(ctx context.Context, name scanners.Name, cfg scanners.Config) (scanners.Scanner, error) {
+	key, err := cacheKey(name, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := c.load(key); ok {
+		ScannerCacheHits.Inc()
+		return s, nil
+	}
+	ScannerCacheMisses.Inc()
+
+	start := time.Now()
+	s, err := cfg.Scanner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ScannerBuildDuration.Observe(time.Since(start).Seconds())
+
+	c.store(key, s)
+	return s, nil
+}
+
+// load returns the cached scanner and marks it as the most recently used one.
+func (c *cache) load(key string) (scanners.Scanner, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*entry).scanner, true
+}
+
+// store caches the scanner and evicts the least recently used ones exceeding the size.
+func (c *cache) store(key string, s scanners.Scanner) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 {
+		return
+	}
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*entry).scanner = s
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key: key, scanner: s})
+	c.evict()
+	ScannerCacheEntries.Set(float64(c.lru.Len()))
+}
+
+// evict removes the least recently used entries exceeding the size. The caller must hold the lock.
+func (c *cache) evict() {
+	for c.lru.Len() > max(c.size, 0) {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.entries, e.Value.(*entry).key)
+		ScannerCacheEvictions.Inc()
+	}
+}
+
+// cacheKey returns the key of the scanner with the given configuration:
+// the scanner's name and the hex encoded SHA-256 hash of the configuration's JSON representation.
+func cacheKey(name scanners.Name, cfg scanners.Config) (string, error) {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal scanner config: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return name.Normalize().String() + "/" + hex.EncodeToString(sum[:]), nil
+}
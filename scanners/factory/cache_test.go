@@ -0,0 +1,83 @@
+package factory
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lvlcn-t/secret-detection-operator/scanners/gitleaks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Get(t *testing.T) {
+	ruleA := &gitleaks.Config{Rules: []gitleaks.Rule{{ID: "a", Regex: "a+"}}}
+	ruleB := &gitleaks.Config{Rules: []gitleaks.Rule{{ID: "b", Regex: "b+"}}}
+
+	tests := []struct {
+		name string
+		size int
+		// gets are the configurations passed to get in order
+		gets []*gitleaks.Config
+		// wantSame are the indices of the gets returning the same scanner as the last one
+		wantSame []int
+		wantLen  int
+	}{
+		{
+			name:     "same config is cached",
+			size:     2,
+			gets:     []*gitleaks.Config{ruleA, ruleA},
+			wantSame: []int{0},
+			wantLen:  1,
+		},
+		{
+			name:     "equal config is cached",
+			size:     2,
+			gets:     []*gitleaks.Config{ruleA, ruleA.DeepCopy()},
+			wantSame: []int{0},
+			wantLen:  1,
+		},
+		{
+			name:    "changed config misses",
+			size:    2,
+			gets:    []*gitleaks.Config{ruleA, ruleB},
+			wantLen: 2,
+		},
+		{
+			name:    "least recently used is evicted",
+			size:    1,
+			gets:    []*gitleaks.Config{ruleA, ruleB, ruleA},
+			wantLen: 1,
+		},
+		{
+			name:     "recently used is kept",
+			size:     2,
+			gets:     []*gitleaks.Config{ruleA, ruleB, ruleA},
+			wantSame: []int{0},
+			wantLen:  2,
+		},
+		{
+			name:    "disabled",
+			size:    0,
+			gets:    []*gitleaks.Config{ruleA, ruleA},
+			wantLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCache(tt.size)
+			got := make([]any, 0, len(tt.gets))
+			for _, cfg := range tt.gets {
+				s, err := c.get(t.Context(), gitleaks.Name, cfg)
+				require.NoError(t, err)
+				got = append(got, s)
+			}
+
+			last := got[len(got)-1]
+			for i := range got[:len(got)-1] {
+				require.Equal(t, slices.Contains(tt.wantSame, i), got[i] == last, "get %d", i)
+			}
+			require.Equal(t, tt.wantLen, c.lru.Len())
+			require.Len(t, c.entries, tt.wantLen)
+		})
+	}
+}
@@ -22,10 +22,11 @@ func newDefaultScanners() map[scanners.Name]scanners.Scanner {
 }
 
 // Get returns the scanner for the given name with default configuration.
+// If a configuration is given, the scanner is built from it and cached, see [SetCacheSize].
 // If the scanner is not found, it returns nil.
 func Get(ctx context.Context, name scanners.Name, cfg scanners.Config) (scanners.Scanner, error) {
 	if !isNilConfig(cfg) {
-		return cfg.Scanner(ctx)
+		return scannerCache.get(ctx, name, cfg)
 	}
 
 	if scanner, ok := defaultScanners[name.Normalize()]; ok {
@@ -0,0 +1,59 @@
+package factory
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// ScannerCacheHits are the total scanners with custom configuration served from the cache
+	ScannerCacheHits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "secret_detection_scanner_cache_hits_total",
+			Help: "Total number of configured scanners served from the cache",
+		},
+	)
+
+	// ScannerCacheMisses are the total scanners with custom configuration that had to be built
+	ScannerCacheMisses = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "secret_detection_scanner_cache_misses_total",
+			Help: "Total number of configured scanners not found in the cache",
+		},
+	)
+
+	// ScannerCacheEvictions are the total scanners evicted from the cache
+	ScannerCacheEvictions = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "secret_detection_scanner_cache_evictions_total",
+			Help: "Total number of configured scanners evicted from the cache",
+		},
+	)
+
+	// ScannerCacheEntries is the number of scanners in the cache
+	ScannerCacheEntries = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "secret_detection_scanner_cache_entries",
+			Help: "Number of configured scanners in the cache",
+		},
+	)
+
+	// ScannerBuildDuration is a histogram of the durations to build a configured scanner
+	ScannerBuildDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "secret_detection_scanner_build_duration_seconds",
+			Help:    "Duration of building a scanner with custom configuration",
+			Buckets: prometheus.DefBuckets,
+		},
+	)
+)
+
+func init() { //nolint:gochecknoinits // Common pattern for controller-runtime
+	metrics.Registry.MustRegister(
+		ScannerCacheHits,
+		ScannerCacheMisses,
+		ScannerCacheEvictions,
+		ScannerCacheEntries,
+		ScannerBuildDuration,
+	)
+}
@@ -0,0 +1,96 @@
+package scanners
+
+import (
+	"strings"
+)
+
+// Finding is a single secret detected by a [Scanner].
+type Finding struct {
+	// RuleID is the identifier of the rule that detected the secret.
+	RuleID string
+	// Description is a human-readable description of the rule.
+	Description string
+	// Start is the byte offset of the match in the scanned value.
+	Start int
+	// End is the byte offset right after the match in the scanned value.
+	End int
+	// StartLine is the 1-based line of the start of the match.
+	StartLine int
+	// EndLine is the 1-based line of the end of the match.
+	EndLine int
+	// Match is the substring of the scanned value that matched the rule.
+	Match string
+	// Secret is the part of the match that is the actual secret.
+	Secret string
+	// Entropy is the Shannon entropy of the secret.
+	Entropy float32
+	// Severity is the severity of the secret.
+	Severity Severity
+}
+
+// Locate sets the byte offsets and lines of the finding based on the position of its match in value.
+// It searches the match starting at the given byte offset, so repeated matches can be located one after another.
+// If the match can't be found, the finding is left unchanged and false is returned.
+func (f *Finding) Locate(value string, from int) bool {
+	if from < 0 || from > len(value) {
+		return false
+	}
+	i := strings.Index(value[from:], f.Match)
+	if i < 0 {
+		return false
+	}
+	f.Start = from + i
+	f.End = f.Start + len(f.Match)
+	f.StartLine = strings.Count(value[:f.Start], "\n") + 1
+	f.EndLine = f.StartLine + strings.Count(f.Match, "\n")
+	return true
+}
+
+// MaxSeverity returns the highest severity of all findings.
+// It returns [SeverityUnknown] if there are no findings.
+func MaxSeverity(findings []Finding) Severity {
+	sev := SeverityUnknown
+	for i := range findings {
+		if findings[i].Severity.Int() > sev.Int() {
+			sev = findings[i].Severity
+		}
+	}
+	return sev
+}
+
+// Primary returns the finding with the highest severity.
+// The first finding wins if multiple findings share the same severity.
+// It returns nil if there are no findings.
+func Primary(findings []Finding) *Finding {
+	var p *Finding
+	for i := range findings {
+		if p == nil || findings[i].Severity.Int() > p.Severity.Int() {
+			p = &findings[i]
+		}
+	}
+	return p
+}
+
+// LegacyRuleID is the rule ID of findings reported by [ScanLegacy].
+const LegacyRuleID = "legacy"
+
+// ScanLegacy implements [Scanner.Scan] on top of [Scanner.IsSecret] and [Scanner.DetectSeverity]
+// for implementations that can't report individual findings.
+// It returns a single finding spanning the whole value if it is a secret.
+func ScanLegacy(s interface {
+	IsSecret(value string) bool
+	DetectSeverity(value string) Severity
+}, value string,
+) []Finding {
+	if !s.IsSecret(value) {
+		return nil
+	}
+	f := Finding{
+		RuleID:   LegacyRuleID,
+		Match:    value,
+		Secret:   value,
+		Severity: s.DetectSeverity(value),
+	}
+	f.Locate(value, 0)
+	return []Finding{f}
+}
@@ -2,6 +2,7 @@ package gitleaks
 
 import (
 	"context"
+	"slices"
 	"strconv"
 
 	"github.com/lvlcn-t/secret-detection-operator/scanners"
@@ -36,7 +37,11 @@ func (c *Config) Scanner(ctx context.Context) (scanners.Scanner, error) {
 
 type Entropy string
 
+// Float64 parses the entropy. An empty entropy means no minimum entropy is required.
 func (e Entropy) Float64() (float64, error) {
+	if e == "" {
+		return 0, nil
+	}
 	return strconv.ParseFloat(string(e), 64)
 }
 
@@ -94,3 +99,41 @@ type AllowlistRule struct {
 	// +optional
 	StopWords []string `json:"stopWords,omitempty"`
 }
+
+// MergeConfigs merges the given configurations into a single configuration.
+// Nil configurations are skipped and nil is returned if all configurations are nil.
+//
+// The default rules are used if any configuration uses them. Rules are merged by their ID;
+// if multiple configurations define a rule with the same ID, the first definition wins.
+// Allowlists are combined, skipping identical allowlist rules.
+func MergeConfigs(configs ...*Config) *Config {
+	var merged *Config
+	seen := map[string]struct{}{}
+	for _, c := range configs {
+		if c == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &Config{}
+		}
+
+		merged.UseDefault = merged.UseDefault || c.UseDefault
+		for _, rule := range c.Rules {
+			if _, ok := seen[rule.ID]; ok {
+				continue
+			}
+			seen[rule.ID] = struct{}{}
+			merged.Rules = append(merged.Rules, *rule.DeepCopy())
+		}
+		for _, allow := range c.Allowlist {
+			if slices.ContainsFunc(merged.Allowlist, func(a AllowlistRule) bool {
+				return a.Description == allow.Description && a.Regex == allow.Regex &&
+					a.Path == allow.Path && slices.Equal(a.StopWords, allow.StopWords)
+			}) {
+				continue
+			}
+			merged.Allowlist = append(merged.Allowlist, *allow.DeepCopy())
+		}
+	}
+	return merged
+}
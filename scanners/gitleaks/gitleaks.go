@@ -117,27 +117,53 @@ func (g *Scanner) Name() scanners.Name {
 	return Name
 }
 
+// Scan scans the given value and returns a finding for every gitleaks rule that matched.
+// The severity of each finding is derived from the entropy of its secret.
+func (g *Scanner) Scan(_ context.Context, value string) ([]scanners.Finding, error) {
+	results := g.detector.DetectString(value)
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	findings := make([]scanners.Finding, 0, len(results))
+	// offsets tracks where to continue searching for repeated matches of the same rule.
+	offsets := map[string]int{}
+	for i := range results {
+		r := &results[i]
+		f := scanners.Finding{
+			RuleID:      r.RuleID,
+			Description: r.Description,
+			Match:       r.Match,
+			Secret:      r.Secret,
+			Entropy:     r.Entropy,
+			Severity:    severity(r.Entropy),
+		}
+		key := r.RuleID + "\x00" + r.Match
+		if f.Locate(value, offsets[key]) {
+			offsets[key] = f.End
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// IsSecret checks if the given value is a secret.
+// It is an adapter for [Scanner.Scan].
 func (g *Scanner) IsSecret(value string) bool {
-	return len(g.detector.DetectString(value)) > 0
+	findings, _ := g.Scan(context.Background(), value)
+	return len(findings) > 0
 }
 
 // DetectSeverity analyzes the candidate secret value and returns a string representing the severity.
-// If no secret is detected, it returns an empty string.
-// It uses a heuristic based on the secret’s length and Shannon entropy.
+// If no secret is detected, it returns [scanners.SeverityUnknown].
+// It is an adapter for [Scanner.Scan].
 func (g *Scanner) DetectSeverity(value string) scanners.Severity {
-	findings := g.detector.DetectString(value)
-	if len(findings) == 0 {
-		return scanners.SeverityUnknown
-	}
-
-	// Select the maximum entropy among all findings.
-	var maxEntropy float32
-	for i := range findings {
-		if findings[i].Entropy > maxEntropy {
-			maxEntropy = findings[i].Entropy
-		}
-	}
+	findings, _ := g.Scan(context.Background(), value)
+	return scanners.MaxSeverity(findings)
+}
 
+// severity maps the Shannon entropy of a secret to a severity.
+func severity(entropy float32) scanners.Severity {
 	// Entropy thresholds for severity levels.
 	const (
 		criticalThreshold = 4.5
@@ -145,11 +171,11 @@ func (g *Scanner) DetectSeverity(value string) scanners.Severity {
 		mediumThreshold   = 3.5
 	)
 	switch {
-	case maxEntropy > criticalThreshold:
+	case entropy > criticalThreshold:
 		return scanners.SeverityCritical
-	case maxEntropy > highThreshold:
+	case entropy > highThreshold:
 		return scanners.SeverityHigh
-	case maxEntropy > mediumThreshold:
+	case entropy > mediumThreshold:
 		return scanners.SeverityMedium
 	default:
 		return scanners.SeverityLow
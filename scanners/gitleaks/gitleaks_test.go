@@ -1,5 +1,13 @@
 package gitleaks
 
+import (
+	"slices"
+	"testing"
+
+	"github.com/lvlcn-t/secret-detection-operator/scanners"
+	"github.com/stretchr/testify/require"
+)
+
 // import (
 // 	"testing"
 
@@ -122,3 +130,73 @@ package gitleaks
 // 	require.NotNil(t, scanner)
 // 	assert.Equal(t, v1alpha1.ScannerGitleaks, scanner.Name())
 // }
+
+func TestScanner_Scan(t *testing.T) {
+	s, err := New(t.Context(), nil)
+	require.NoError(t, err)
+
+	token := "ghp_" + "1234567890abcdef1234567890abcdef12345678"
+	value := "# config\nuser: admin\ntoken: " + token + "\n"
+	findings, err := s.Scan(t.Context(), value)
+	require.NoError(t, err)
+
+	i := slices.IndexFunc(findings, func(f scanners.Finding) bool { return f.RuleID == "github-pat" })
+	require.GreaterOrEqual(t, i, 0, "github-pat rule didn't match")
+	f := findings[i]
+	require.Equal(t, 3, f.StartLine)
+	require.Equal(t, f.Match, value[f.Start:f.End])
+	require.Contains(t, token, f.Secret)
+	require.Equal(t, scanners.MaxSeverity(findings), s.DetectSeverity(value))
+	require.True(t, s.IsSecret(value))
+
+	findings, err = s.Scan(t.Context(), "nothing to see here")
+	require.NoError(t, err)
+	require.Empty(t, findings)
+}
+
+func TestMergeConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []*Config
+		want    *Config
+	}{
+		{
+			name:    "all nil",
+			configs: []*Config{nil, nil},
+			want:    nil,
+		},
+		{
+			name: "first rule definition wins",
+			configs: []*Config{
+				nil,
+				{Rules: []Rule{{ID: "a", Regex: "a+"}, {ID: "shared", Regex: "first"}}},
+				{
+					UseDefault: true,
+					Rules:      []Rule{{ID: "shared", Regex: "second"}, {ID: "b", Regex: "b+"}},
+					Allowlist:  []AllowlistRule{{StopWords: []string{"example"}}},
+				},
+			},
+			want: &Config{
+				UseDefault: true,
+				Rules:      []Rule{{ID: "a", Regex: "a+"}, {ID: "shared", Regex: "first"}, {ID: "b", Regex: "b+"}},
+				Allowlist:  []AllowlistRule{{StopWords: []string{"example"}}},
+			},
+		},
+		{
+			name: "identical allowlist rules are combined",
+			configs: []*Config{
+				{Allowlist: []AllowlistRule{{Regex: "dummy"}, {Path: "test/*"}}},
+				{Allowlist: []AllowlistRule{{Regex: "dummy"}, {Regex: "example"}}},
+			},
+			want: &Config{
+				Allowlist: []AllowlistRule{{Regex: "dummy"}, {Path: "test/*"}, {Regex: "example"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, MergeConfigs(tt.configs...))
+		})
+	}
+}
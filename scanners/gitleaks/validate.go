@@ -0,0 +1,68 @@
+package gitleaks
+
+import (
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Validate validates the configuration the same way [New] interprets it.
+// Rules and allowlist rules that [New] would skip at runtime are reported as field errors.
+func (c *Config) Validate(fldPath *field.Path) field.ErrorList {
+	if c == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	ids := map[string]struct{}{}
+	for i := range c.Rules {
+		rulePath := fldPath.Child("rules").Index(i)
+		rule := &c.Rules[i]
+		if rule.ID == "" {
+			errs = append(errs, field.Required(rulePath.Child("id"), "rule ID must not be empty"))
+		} else if _, ok := ids[rule.ID]; ok {
+			errs = append(errs, field.Duplicate(rulePath.Child("id"), rule.ID))
+		}
+		ids[rule.ID] = struct{}{}
+		errs = append(errs, rule.validate(rulePath)...)
+	}
+
+	for i := range c.Allowlist {
+		errs = append(errs, c.Allowlist[i].validate(fldPath.Child("allowlist").Index(i))...)
+	}
+	return errs
+}
+
+// validate validates a single rule.
+func (r *Rule) validate(fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if r.Regex == "" {
+		errs = append(errs, field.Required(fldPath.Child("regex"), "rule must define a regex"))
+	} else if regex, err := regexp.Compile(r.Regex); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Child("regex"), r.Regex, err.Error()))
+	} else if r.SecretGroup < 0 || r.SecretGroup > regex.NumSubexp() {
+		errs = append(errs, field.Invalid(fldPath.Child("secretGroup"), r.SecretGroup, "must refer to a capture group of the regex"))
+	}
+
+	if entropy, err := r.Entropy.Float64(); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Child("entropy"), r.Entropy, "must be a number"))
+	} else if entropy < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("entropy"), r.Entropy, "must not be negative"))
+	}
+	return errs
+}
+
+// validate validates a single allowlist rule.
+func (a *AllowlistRule) validate(fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if a.Regex == "" && a.Path == "" && len(a.StopWords) == 0 {
+		errs = append(errs, field.Required(fldPath, "allowlist rule must define a regex, path or stop words"))
+	}
+	if _, err := regexp.Compile(a.Regex); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Child("regex"), a.Regex, err.Error()))
+	}
+	if _, err := regexp.Compile(a.Path); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Child("path"), a.Path, err.Error()))
+	}
+	return errs
+}
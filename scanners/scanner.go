@@ -8,8 +8,13 @@ import (
 type Scanner interface {
 	// Name returns the name of the scanner.
 	Name() Name
+	// Scan scans the given value and returns a [Finding] for every secret detected in it.
+	// It returns no findings if the value doesn't contain a secret.
+	Scan(ctx context.Context, value string) ([]Finding, error)
 	// IsSecret checks if the given value is a secret.
 	// It returns true if the value is a secret, false otherwise.
+	//
+	// Deprecated: Use [Scanner.Scan] instead. Implementations may build it on top of Scan.
 	IsSecret(value string) bool
 	// DetectSeverity analyzes the candidate secret value and returns a string representing the severity.
 	// If no secret is detected, it returns an empty string.
@@ -20,6 +25,8 @@ type Scanner interface {
 	// 	- High: 4.0
 	// 	- Medium: 3.5
 	// 	- Low: < 3.5
+	//
+	// Deprecated: Use [Scanner.Scan] and [MaxSeverity] instead. Implementations may build it on top of Scan.
 	DetectSeverity(value string) Severity
 }
 
@@ -4,6 +4,7 @@
 package scanners
 
 import (
+	"context"
 	"sync"
 )
 
@@ -26,6 +27,9 @@ var _ Scanner = &ScannerMock{}
 //			NameFunc: func() Name {
 //				panic("mock out the Name method")
 //			},
+//			ScanFunc: func(ctx context.Context, value string) ([]Finding, error) {
+//				panic("mock out the Scan method")
+//			},
 //		}
 //
 //		// use mockedScanner in code that requires Scanner
@@ -42,6 +46,9 @@ type ScannerMock struct {
 	// NameFunc mocks the Name method.
 	NameFunc func() Name
 
+	// ScanFunc mocks the Scan method.
+	ScanFunc func(ctx context.Context, value string) ([]Finding, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// DetectSeverity holds details about calls to the DetectSeverity method.
@@ -57,10 +64,18 @@ type ScannerMock struct {
 		// Name holds details about calls to the Name method.
 		Name []struct {
 		}
+		// Scan holds details about calls to the Scan method.
+		Scan []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Value is the value argument value.
+			Value string
+		}
 	}
 	lockDetectSeverity sync.RWMutex
 	lockIsSecret       sync.RWMutex
 	lockName           sync.RWMutex
+	lockScan           sync.RWMutex
 }
 
 // DetectSeverity calls DetectSeverityFunc.
@@ -153,3 +168,39 @@ func (mock *ScannerMock) NameCalls() []struct {
 	mock.lockName.RUnlock()
 	return calls
 }
+
+// Scan calls ScanFunc.
+func (mock *ScannerMock) Scan(ctx context.Context, value string) ([]Finding, error) {
+	if mock.ScanFunc == nil {
+		panic("ScannerMock.ScanFunc: method is nil but Scanner.Scan was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Value string
+	}{
+		Ctx:   ctx,
+		Value: value,
+	}
+	mock.lockScan.Lock()
+	mock.calls.Scan = append(mock.calls.Scan, callInfo)
+	mock.lockScan.Unlock()
+	return mock.ScanFunc(ctx, value)
+}
+
+// ScanCalls gets all the calls that were made to Scan.
+// Check the length with:
+//
+//	len(mockedScanner.ScanCalls())
+func (mock *ScannerMock) ScanCalls() []struct {
+	Ctx   context.Context
+	Value string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Value string
+	}
+	mock.lockScan.RLock()
+	calls = mock.calls.Scan
+	mock.lockScan.RUnlock()
+	return calls
+}
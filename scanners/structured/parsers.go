@@ -0,0 +1,212 @@
+package structured
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// bufferSize determines how far into the stream the decoder
+// will look to figure out whether the input is YAML or JSON.
+const bufferSize = 1024
+
+// parseYAML parses a (multi-document) YAML stream.
+// Documents of a multi-document stream are addressed like array elements ($[0], $[1], ...).
+func parseYAML(content string) ([]Leaf, error) {
+	d := yaml.NewYAMLOrJSONDecoder(strings.NewReader(content), bufferSize)
+	var docs []any
+	for {
+		var doc any
+		err := d.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+
+	var leaves []Leaf
+	for i, doc := range docs {
+		if !isContainer(doc) {
+			// A plain scalar is not a structured document.
+			return nil, errors.New("document is not a mapping or sequence")
+		}
+		p := root
+		if len(docs) > 1 {
+			p = index(root, i)
+		}
+		leaves = flatten(p, "", doc, leaves)
+	}
+	return leaves, nil
+}
+
+// parseJSON parses a JSON document.
+func parseJSON(content string) ([]Leaf, error) {
+	d := json.NewDecoder(strings.NewReader(content))
+	d.UseNumber()
+	var doc any
+	if err := d.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if !isContainer(doc) {
+		return nil, errors.New("document is not an object or array")
+	}
+	return flatten(root, "", doc, nil), nil
+}
+
+// parseTOML parses a TOML document.
+func parseTOML(content string) ([]Leaf, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, err
+	}
+	return flatten(root, "", doc, nil), nil
+}
+
+// parseINI parses an INI file. Keys outside of a section are addressed from the root.
+func parseINI(content string) ([]Leaf, error) {
+	var (
+		leaves  []Leaf
+		section = root
+	)
+	err := scanLines(content, func(line string) error {
+		if strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			return nil
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("invalid section header %q", line)
+			}
+			section = root
+			for part := range strings.SplitSeq(strings.Trim(line, "[]"), ".") {
+				section = child(section, strings.TrimSpace(part))
+			}
+			return nil
+		}
+
+		key, value, ok := cutAny(line, "=:")
+		if !ok {
+			return fmt.Errorf("invalid line %q", line)
+		}
+		leaves = append(leaves, Leaf{Path: child(section, key), Name: key, Value: unquote(value)})
+		return nil
+	})
+	return leaves, err
+}
+
+// parseEnv parses a dotenv file.
+func parseEnv(content string) ([]Leaf, error) {
+	var leaves []Leaf
+	err := scanLines(content, func(line string) error {
+		if strings.HasPrefix(line, "#") {
+			return nil
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return fmt.Errorf("invalid line %q", line)
+		}
+		key = strings.TrimSpace(key)
+		leaves = append(leaves, Leaf{Path: child(root, key), Name: key, Value: unquote(strings.TrimSpace(value))})
+		return nil
+	})
+	return leaves, err
+}
+
+// propertiesLine matches a single "key=value", "key: value" or "key value" line of a .properties file.
+var propertiesLine = regexp.MustCompile(`^([^=:\s]+)\s*(?:[=:]\s*|\s+)(.*)$`)
+
+// parseProperties parses a Java .properties file.
+// Keys are kept as a whole, e.g. "$['spring.datasource.password']".
+func parseProperties(content string) ([]Leaf, error) {
+	var (
+		leaves []Leaf
+		cont   string
+	)
+	err := scanLines(content, func(line string) error {
+		if cont == "" && (strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!")) {
+			return nil
+		}
+		// Join continued lines that end with a single backslash.
+		if strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) {
+			cont += strings.TrimSuffix(line, `\`)
+			return nil
+		}
+		line, cont = cont+line, ""
+
+		m := propertiesLine.FindStringSubmatch(line)
+		if m == nil {
+			return fmt.Errorf("invalid line %q", line)
+		}
+		leaves = append(leaves, Leaf{Path: child(root, m[1]), Name: m[1], Value: m[2]})
+		return nil
+	})
+	return leaves, err
+}
+
+// isProperties reports whether every non-comment line of the content is a "key=value" pair.
+func isProperties(content string) bool {
+	err := scanLines(content, func(line string) error {
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			return nil
+		}
+		key, _, ok := strings.Cut(line, "=")
+		if !ok || strings.ContainsAny(strings.TrimSpace(key), " \t:") {
+			return errors.New("not a properties line")
+		}
+		return nil
+	})
+	return err == nil
+}
+
+// scanLines calls fn for every non-empty, trimmed line of the content.
+func scanLines(content string, fn func(line string) error) error {
+	s := bufio.NewScanner(strings.NewReader(content))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// cutAny slices s around the first occurrence of any of the separators.
+func cutAny(s, separators string) (before, after string, found bool) {
+	i := strings.IndexAny(s, separators)
+	if i < 0 {
+		return s, "", false
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:]), true
+}
+
+// unquote removes matching single or double quotes around a value.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// isContainer reports whether the decoded value is a map or a slice.
+func isContainer(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,171 @@
+// Package structured parses structured configuration files (YAML, JSON, .properties, .env, INI, TOML)
+// into their leaf values, so that every value can be scanned and reported with its nested path.
+package structured
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Format represents the format of a structured configuration file.
+type Format string
+
+// String returns the string representation of the format.
+func (f Format) String() string {
+	return string(f)
+}
+
+const (
+	// FormatUnknown is used for values that are not a known structured format.
+	FormatUnknown Format = ""
+	// FormatYAML is used for YAML documents (including multi-document streams).
+	FormatYAML Format = "yaml"
+	// FormatJSON is used for JSON documents.
+	FormatJSON Format = "json"
+	// FormatProperties is used for Java .properties files.
+	FormatProperties Format = "properties"
+	// FormatEnv is used for dotenv files.
+	FormatEnv Format = "env"
+	// FormatINI is used for INI files.
+	FormatINI Format = "ini"
+	// FormatTOML is used for TOML documents.
+	FormatTOML Format = "toml"
+)
+
+// Leaf is a single scalar value of a structured configuration file.
+type Leaf struct {
+	// Path is the JSONPath of the value, e.g. "$.spring.datasource.password".
+	Path string
+	// Name is the last segment of the path (the name of the field holding the value).
+	Name string
+	// Value is the string representation of the value.
+	Value string
+}
+
+// String returns the leaf as a "name: value" pair.
+// The field name is kept in front of the value, because many detection rules
+// rely on it (e.g. "password: ..." or "api_key: ...") to identify a secret.
+func (l Leaf) String() string {
+	return l.Name + ": " + l.Value
+}
+
+// extensions maps file extensions to their format.
+var extensions = map[string]Format{
+	".yaml":       FormatYAML,
+	".yml":        FormatYAML,
+	".json":       FormatJSON,
+	".properties": FormatProperties,
+	".env":        FormatEnv,
+	".ini":        FormatINI,
+	".cfg":        FormatINI,
+	".toml":       FormatTOML,
+}
+
+// Guess returns the format of the given value.
+// The format is derived from the extension of the key (or file name) and
+// falls back to sniffing the content for JSON, YAML and .properties files.
+// It returns [FormatUnknown] if the value doesn't look like a structured file.
+func Guess(key, content string) Format {
+	base := path.Base(key)
+	if base == ".env" || strings.HasPrefix(base, ".env.") {
+		return FormatEnv
+	}
+	if f, ok := extensions[strings.ToLower(path.Ext(base))]; ok {
+		return f
+	}
+
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case trimmed == "":
+		return FormatUnknown
+	case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+		if _, err := parseJSON(trimmed); err == nil {
+			return FormatJSON
+		}
+	case !strings.Contains(trimmed, "\n"):
+		// A single line is most likely a plain value, which we scan as is.
+		return FormatUnknown
+	case isProperties(trimmed):
+		return FormatProperties
+	}
+
+	if leaves, err := parseYAML(trimmed); err == nil && len(leaves) > 0 {
+		return FormatYAML
+	}
+	return FormatUnknown
+}
+
+// Parse parses the content in the given format and returns all leaf values.
+// The leaves are sorted by their path.
+func Parse(format Format, content string) ([]Leaf, error) {
+	var (
+		leaves []Leaf
+		err    error
+	)
+	switch format {
+	case FormatYAML:
+		leaves, err = parseYAML(content)
+	case FormatJSON:
+		leaves, err = parseJSON(content)
+	case FormatProperties:
+		leaves, err = parseProperties(content)
+	case FormatEnv:
+		leaves, err = parseEnv(content)
+	case FormatINI:
+		leaves, err = parseINI(content)
+	case FormatTOML:
+		leaves, err = parseTOML(content)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", format, err)
+	}
+
+	slices.SortFunc(leaves, func(a, b Leaf) int { return strings.Compare(a.Path, b.Path) })
+	return leaves, nil
+}
+
+// root is the JSONPath of the document root.
+const root = "$"
+
+// identifier matches path segments that can be written in dot notation.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// child returns the JSONPath of the named child of parent.
+func child(parent, name string) string {
+	if identifier.MatchString(name) {
+		return parent + "." + name
+	}
+	return parent + "['" + strings.ReplaceAll(name, "'", `\'`) + "']"
+}
+
+// index returns the JSONPath of the i-th element of parent.
+func index(parent string, i int) string {
+	return parent + "[" + strconv.Itoa(i) + "]"
+}
+
+// flatten walks the decoded document and collects all scalar values.
+func flatten(p, name string, v any, leaves []Leaf) []Leaf {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, c := range val {
+			leaves = flatten(child(p, k), k, c, leaves)
+		}
+	case []any:
+		for i, c := range val {
+			leaves = flatten(index(p, i), name, c, leaves)
+		}
+	case nil:
+		// Null values can't hold a secret.
+	case string:
+		leaves = append(leaves, Leaf{Path: p, Name: name, Value: val})
+	default:
+		leaves = append(leaves, Leaf{Path: p, Name: name, Value: fmt.Sprint(val)})
+	}
+	return leaves
+}
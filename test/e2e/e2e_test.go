@@ -43,11 +43,12 @@ func TestE2E_DetectSecret_ReportOnly(t *testing.T) {
 		Data:       map[string]string{"password": reportOnlyDetectedValue},
 	}
 
+	name := v1alpha1.NewExposedSecretName(cm, "password")
 	e2e.
 		WithScanPolicy(policy).
 		WithConfigMap(cm).
 		WithAssertion(func(e *test.E2E) {
-			es, err := e.WaitForExposedSecretPhase(ctx, ns.Name, "cm-password", v1alpha1.PhaseDetected)
+			es, err := e.WaitForExposedSecretPhase(ctx, ns.Name, name, v1alpha1.PhaseDetected)
 			require.NoError(t, err)
 			require.Equal(t, v1alpha1.ActionReportOnly, es.Spec.Action)
 			require.Equal(t, v1alpha1.PhaseDetected, es.Status.Phase)
@@ -80,25 +81,26 @@ func TestE2E_AutoRemediate(t *testing.T) {
 		Data:       map[string]string{"password": autoRemediateDetectedValue},
 	}
 
+	name := v1alpha1.NewExposedSecretName(cm, "password")
 	e2e.
 		WithScanPolicy(policy).
 		WithConfigMap(cm).
 		WithAssertion(func(e *test.E2E) {
-			es, err := e.WaitForExposedSecretPhase(ctx, ns.Name, "cm-password", v1alpha1.PhaseRemediated)
+			es, err := e.WaitForExposedSecretPhase(ctx, ns.Name, name, v1alpha1.PhaseRemediated)
 			require.NoError(t, err)
 			require.Equal(t, v1alpha1.ActionAutoRemediate, es.Spec.Action)
 			require.NotNil(t, es.Status.CreatedSecretRef)
-			require.Equal(t, "cm-password", es.Status.CreatedSecretRef.Name)
+			require.Equal(t, name, es.Status.CreatedSecretRef.Name)
 		}).
 		WithAssertion(func(e *test.E2E) {
-			secret, err := e.WaitForSecret(ctx, ns.Name, "cm-password")
+			secret, err := e.WaitForSecret(ctx, ns.Name, name)
 			require.NoError(t, err)
 			require.Equal(t, autoRemediateDetectedValue, string(secret.Data["password"]))
 		}).
 		WithAssertion(func(e *test.E2E) {
 			updatedCM, err := e.WaitForConfigMapKeyAbsent(ctx, ns.Name, "cm", "password")
 			require.NoError(t, err)
-			require.Equal(t, "cm-password", updatedCM.Annotations[v1alpha1.AnnotationExposedSecret])
+			require.Equal(t, name, updatedCM.Annotations[v1alpha1.AnnotationExposedSecret])
 		}).
 		Run()
 }
@@ -134,12 +136,12 @@ func TestE2E_ScanPolicyScoping(t *testing.T) {
 		WithConfigMap(cmWithPolicy).
 		WithConfigMap(cmWithoutPolicy).
 		WithAssertion(func(e *test.E2E) {
-			esIgnored, err := e.WaitForExposedSecretPhase(ctx, nsWithPolicy.Name, "cm-password", v1alpha1.PhaseIgnored)
+			esIgnored, err := e.WaitForExposedSecretPhase(ctx, nsWithPolicy.Name, v1alpha1.NewExposedSecretName(cmWithPolicy, "password"), v1alpha1.PhaseIgnored)
 			require.NoError(t, err)
 			require.Equal(t, v1alpha1.ActionIgnore, esIgnored.Spec.Action)
 		}).
 		WithAssertion(func(e *test.E2E) {
-			esDetected, err := e.WaitForExposedSecretPhase(ctx, nsWithoutPolicy.Name, "cm-password", v1alpha1.PhaseDetected)
+			esDetected, err := e.WaitForExposedSecretPhase(ctx, nsWithoutPolicy.Name, v1alpha1.NewExposedSecretName(cmWithoutPolicy, "password"), v1alpha1.PhaseDetected)
 			require.NoError(t, err)
 			require.Equal(t, v1alpha1.ActionReportOnly, esDetected.Spec.Action)
 		}).
@@ -166,11 +168,12 @@ func TestE2E_IgnoredViaPolicy(t *testing.T) {
 		Data:       map[string]string{"password": ignoredDetectedValue},
 	}
 
+	name := v1alpha1.NewExposedSecretName(cm, "password")
 	e2e.
 		WithScanPolicy(policy).
 		WithConfigMap(cm).
 		WithAssertion(func(e *test.E2E) {
-			es, err := e.WaitForExposedSecretPhase(ctx, ns.Name, "cm-password", v1alpha1.PhaseIgnored)
+			es, err := e.WaitForExposedSecretPhase(ctx, ns.Name, name, v1alpha1.PhaseIgnored)
 			require.NoError(t, err)
 			require.Equal(t, v1alpha1.ActionIgnore, es.Spec.Action)
 			require.Equal(t, v1alpha1.PhaseIgnored, es.Status.Phase)
@@ -178,7 +181,7 @@ func TestE2E_IgnoredViaPolicy(t *testing.T) {
 		}).
 		WithAssertion(func(e *test.E2E) {
 			secret := &corev1.Secret{}
-			err := e.Client.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: "cm-password"}, secret)
+			err := e.Client.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: name}, secret)
 			require.Error(t, err)
 			require.True(t, apierrors.IsNotFound(err))
 		}).
@@ -5,8 +5,11 @@ import (
 
 	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
 	"github.com/lvlcn-t/secret-detection-operator/config"
+	"github.com/lvlcn-t/secret-detection-operator/controllers"
 	"github.com/lvlcn-t/secret-detection-operator/test/data"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -30,8 +33,11 @@ func (f *Framework) Unit(t testing.TB) *Unittest {
 	// Always make sure our default config is valid.
 	require.NoError(t, cfg.Validate(t.Context(), fake.NewClientBuilder().WithScheme(scheme).Build()))
 	return &Unittest{
-		T:          t,
-		builder:    fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.ExposedSecret{}, &v1alpha1.ScanPolicy{}),
+		T: t,
+		builder: fake.NewClientBuilder().WithScheme(scheme).
+			WithStatusSubresource(&v1alpha1.ExposedSecret{}, &v1alpha1.ScanPolicy{}, &v1alpha1.ClusterScanPolicy{}, &v1alpha1.SecretFingerprint{}).
+			WithIndex(&v1alpha1.ExposedSecret{}, controllers.IndexExposedSecretConfigMap, controllers.IndexExposedSecretByConfigMap).
+			WithIndex(&v1alpha1.ExposedSecret{}, controllers.IndexExposedSecretFingerprint, controllers.IndexExposedSecretByFingerprint),
 		cfg:        cfg,
 		scheme:     scheme,
 		assertions: []func(*Unittest, ctrl.Result, error){},
@@ -53,11 +59,13 @@ func (f *Framework) E2E(t testing.TB) *E2E {
 }
 
 // newScheme creates a new [runtime.Scheme] for the test.
-// It includes the corev1 and v1alpha1 schemes.
+// It includes the corev1, appsv1, batchv1 and v1alpha1 schemes.
 func newScheme(t testing.TB) *runtime.Scheme {
 	t.Helper()
 	scheme := runtime.NewScheme()
 	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
 	require.NoError(t, v1alpha1.AddToScheme(scheme))
 	return scheme
 }
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"log/slog"
 	"strings"
 	"testing"
@@ -15,23 +16,45 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
-const secretValue = "my-secret"
+const (
+	secretValue = "my-secret"
+	// DefaultRuleID is the rule ID reported by the [DefaultScanner].
+	DefaultRuleID = "test-rule"
+	// recorderBufferSize is the number of Events the [Unittest.Recorder] can hold.
+	recorderBufferSize = 100
+)
 
 var DefaultScanner = &scanners.ScannerMock{
 	NameFunc:           func() scanners.Name { return gitleaks.Name },
 	IsSecretFunc:       func(value string) bool { return strings.Contains(value, secretValue) },
 	DetectSeverityFunc: func(_ string) scanners.Severity { return scanners.SeverityHigh },
+	ScanFunc: func(_ context.Context, value string) ([]scanners.Finding, error) {
+		var findings []scanners.Finding
+		for from := 0; ; {
+			f := scanners.Finding{RuleID: DefaultRuleID, Match: secretValue, Secret: secretValue, Severity: scanners.SeverityHigh}
+			if !f.Locate(value, from) {
+				return findings, nil
+			}
+			findings = append(findings, f)
+			from = f.End
+		}
+	},
 }
 
 type Unittest struct {
-	T          testing.TB
-	Client     client.Client
+	T      testing.TB
+	Client client.Client
+	// Recorder records the Events emitted during the reconciliation.
+	Recorder *events.FakeRecorder
+	// Reconciler is the reconciler under test, e.g. to reconcile the ConfigMap again in an assertion.
+	Reconciler *controllers.ConfigMapReconciler
 	builder    *fake.ClientBuilder
 	cfg        *config.Config
 	cfgMap     *corev1.ConfigMap
@@ -46,7 +69,7 @@ func (t *Unittest) WithConfig(cfg *config.Config) *Unittest {
 	if cfg == nil {
 		return t
 	}
-	require.NoError(t.T, cfg.Validate(t.T.Context(), t.Client))
+	require.NoError(t.T, cfg.Validate(t.T.Context(), fake.NewClientBuilder().WithScheme(t.scheme).Build()))
 	t.cfg = cfg
 	return t
 }
@@ -60,6 +83,31 @@ func (t *Unittest) WithScanPolicy(policy *v1alpha1.ScanPolicy) *Unittest {
 	return t
 }
 
+func (t *Unittest) WithClusterScanPolicy(policy *v1alpha1.ClusterScanPolicy) *Unittest {
+	t.T.Helper()
+	if policy == nil {
+		return t
+	}
+	t.builder = t.builder.WithObjects(policy).WithStatusSubresource(policy)
+	return t
+}
+
+func (t *Unittest) WithNamespace(ns *corev1.Namespace) *Unittest {
+	t.T.Helper()
+	if ns == nil {
+		return t
+	}
+	t.builder = t.builder.WithObjects(ns)
+	return t
+}
+
+// WithObjects adds arbitrary objects to the cluster, e.g. workloads referencing the ConfigMap.
+func (t *Unittest) WithObjects(objs ...client.Object) *Unittest {
+	t.T.Helper()
+	t.builder = t.builder.WithObjects(objs...)
+	return t
+}
+
 func (t *Unittest) WithConfigMap(cm *corev1.ConfigMap) *Unittest {
 	t.T.Helper()
 	if cm == nil {
@@ -70,6 +118,14 @@ func (t *Unittest) WithConfigMap(cm *corev1.ConfigMap) *Unittest {
 	return t
 }
 
+// WithDeletedConfigMap reconciles the ConfigMap without adding it to the cluster,
+// as if it was deleted before the reconciliation.
+func (t *Unittest) WithDeletedConfigMap(cm *corev1.ConfigMap) *Unittest {
+	t.T.Helper()
+	t.cfgMap = cm
+	return t
+}
+
 func (t *Unittest) WithInterceptor(interceptor interceptor.Funcs) *Unittest { //nolint:gocritic // performance is irrelevant when testing
 	t.T.Helper()
 	t.builder = t.builder.WithInterceptorFuncs(interceptor)
@@ -97,7 +153,9 @@ func (t *Unittest) WantError(err bool) *Unittest {
 func (t *Unittest) Run() {
 	t.T.Helper()
 	t.Client = t.builder.Build()
-	r := controllers.NewConfigMapReconciler(t.Client, t.scheme, t.cfg)
+	t.Recorder = events.NewFakeRecorder(recorderBufferSize)
+	r := controllers.NewConfigMapReconciler(t.Client, t.scheme, t.cfg, t.Recorder)
+	t.Reconciler = r
 	ctx := logr.NewContextWithSlogLogger(t.T.Context(), slog.Default())
 
 	require.NotNil(t.T, t.cfgMap, "ConfigMap is required for the test")
@@ -0,0 +1,116 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"github.com/lvlcn-t/secret-detection-operator/controllers"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate--v1-configmap,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=configmaps,verbs=create;update,versions=v1,name=vconfigmap.secretdetection.lvlcn-t.dev,admissionReviewVersions=v1
+
+// Decisions of the [ConfigMapValidator] recorded in [controllers.ConfigMapAdmissions].
+const (
+	decisionAllowed = "allowed"
+	decisionWarned  = "warned"
+	decisionDenied  = "denied"
+)
+
+var _ admission.Validator[*corev1.ConfigMap] = (*ConfigMapValidator)(nil)
+
+// Reviewer scans ConfigMaps on admission, see [controllers.ConfigMapReconciler.Review].
+type Reviewer interface {
+	Review(ctx context.Context, cm *corev1.ConfigMap) (*controllers.Review, error)
+}
+
+// ConfigMapValidator denies or warns about ConfigMaps containing secrets
+// depending on the admission mode of the namespace's effective policy.
+// Writes of the operator itself, e.g. reverting a remediation, are always admitted.
+type ConfigMapValidator struct {
+	reviewer Reviewer
+	operator string
+}
+
+// NewConfigMapValidator creates a new [ConfigMapValidator].
+// The operator is the username of the operator, see [Username]; if empty, all writes are reviewed.
+func NewConfigMapValidator(r Reviewer, operator string) *ConfigMapValidator {
+	return &ConfigMapValidator{reviewer: r, operator: operator}
+}
+
+// SetupWithManager registers the validating webhook for ConfigMaps with the manager.
+func (v *ConfigMapValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &corev1.ConfigMap{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate reviews a newly created ConfigMap.
+func (v *ConfigMapValidator) ValidateCreate(ctx context.Context, cm *corev1.ConfigMap) (admission.Warnings, error) {
+	return v.validate(ctx, cm)
+}
+
+// ValidateUpdate reviews an updated ConfigMap.
+func (v *ConfigMapValidator) ValidateUpdate(ctx context.Context, _, cm *corev1.ConfigMap) (admission.Warnings, error) {
+	return v.validate(ctx, cm)
+}
+
+// ValidateDelete allows all deletions.
+func (v *ConfigMapValidator) ValidateDelete(context.Context, *corev1.ConfigMap) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate reviews the ConfigMap and decides based on the admission mode of the effective policy.
+// The secret values are never part of the response.
+func (v *ConfigMapValidator) validate(ctx context.Context, cm *corev1.ConfigMap) (admission.Warnings, error) {
+	if isOperator(ctx, v.operator) {
+		controllers.ConfigMapAdmissions.WithLabelValues(cm.Namespace, decisionAllowed).Inc()
+		return nil, nil
+	}
+
+	review, err := v.reviewer.Review(ctx, cm)
+	if err != nil {
+		// Fail open like the webhook's failure policy, so a broken policy
+		// or scanner doesn't block all ConfigMaps in the namespace.
+		logr.FromContextAsSlogLogger(ctx).ErrorContext(ctx, "Failed to review ConfigMap", "ConfigMap", cm.Name, "error", err)
+		controllers.ConfigMapAdmissions.WithLabelValues(cm.Namespace, decisionAllowed).Inc()
+		return admission.Warnings{"ConfigMap couldn't be scanned for secrets: " + err.Error()}, nil
+	}
+	if len(review.Findings) == 0 {
+		controllers.ConfigMapAdmissions.WithLabelValues(cm.Namespace, decisionAllowed).Inc()
+		return nil, nil
+	}
+
+	findings := make([]string, 0, len(review.Findings))
+	for i := range review.Findings {
+		findings = append(findings, review.Findings[i].String())
+	}
+
+	switch review.Mode {
+	case v1alpha1.AdmissionEnforce:
+		controllers.ConfigMapAdmissions.WithLabelValues(cm.Namespace, decisionDenied).Inc()
+		return nil, apierrors.NewForbidden(corev1.Resource("configmaps"), cm.Name,
+			fmt.Errorf("ConfigMap contains secrets (policy %s): %s; move them to a Secret", review.Policy, strings.Join(findings, "; ")))
+	case v1alpha1.AdmissionWarn, v1alpha1.AdmissionRemediate:
+		controllers.ConfigMapAdmissions.WithLabelValues(cm.Namespace, decisionWarned).Inc()
+		// ConfigMaps using generateName are marked on admission and remediated by the reconciliation once they are stored.
+		suffix := ""
+		if cm.Annotations[v1alpha1.AnnotationRemediate] == "true" {
+			suffix = ", it is moved to a Secret once the ConfigMap is stored"
+		}
+		warnings := make(admission.Warnings, 0, len(findings))
+		for _, f := range findings {
+			warnings = append(warnings, fmt.Sprintf("ConfigMap contains a secret at %s (policy %s)%s", f, review.Policy, suffix))
+		}
+		return warnings, nil
+	default:
+		controllers.ConfigMapAdmissions.WithLabelValues(cm.Namespace, decisionAllowed).Inc()
+		return nil, nil
+	}
+}
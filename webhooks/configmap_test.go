@@ -0,0 +1,146 @@
+package webhooks
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"github.com/lvlcn-t/secret-detection-operator/config"
+	"github.com/lvlcn-t/secret-detection-operator/controllers"
+	"github.com/lvlcn-t/secret-detection-operator/scanners"
+	"github.com/lvlcn-t/secret-detection-operator/scanners/factory"
+	"github.com/lvlcn-t/secret-detection-operator/test"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// operator is the username the ConfigMap webhooks are configured to admit writes from.
+const operator = "system:serviceaccount:secret-detection-system:secret-detection-operator"
+
+func TestConfigMapValidator(t *testing.T) {
+	const secretValue = "my-secret"
+	name := v1alpha1.NewExposedSecretName(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}, "token")
+	policy := func(mode v1alpha1.AdmissionMode, action v1alpha1.Action) *v1alpha1.ScanPolicy {
+		return &v1alpha1.ScanPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol"},
+			Spec: v1alpha1.ScanPolicySpec{
+				Action:        action,
+				MinSeverity:   scanners.SeverityLow,
+				Scanner:       test.DefaultScanner.Name(),
+				HashAlgorithm: v1alpha1.AlgorithmSHA256,
+				ExcludedKeys:  []string{"excluded"},
+				AdmissionMode: mode,
+			},
+		}
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+		Data: map[string]string{
+			"token":    secretValue,
+			"excluded": secretValue,
+			"plain":    "hello",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		policy       *v1alpha1.ScanPolicy
+		existing     *v1alpha1.ExposedSecret
+		username     string
+		wantDenied   bool
+		wantWarnings int
+	}{
+		{
+			name:       "enforce denies secrets",
+			policy:     policy(v1alpha1.AdmissionEnforce, v1alpha1.ActionReportOnly),
+			wantDenied: true,
+		},
+		{
+			name:     "enforce admits writes of the operator",
+			policy:   policy(v1alpha1.AdmissionEnforce, v1alpha1.ActionReportOnly),
+			username: operator,
+		},
+		{
+			name:         "warn admits with warnings",
+			policy:       policy(v1alpha1.AdmissionWarn, v1alpha1.ActionAutoRemediate),
+			wantWarnings: 1,
+		},
+		{
+			name:   "off admits without scanning",
+			policy: policy(v1alpha1.AdmissionOff, v1alpha1.ActionReportOnly),
+		},
+		{
+			name:   "ignored secrets are admitted",
+			policy: policy(v1alpha1.AdmissionEnforce, v1alpha1.ActionIgnore),
+		},
+		{
+			name:   "user override on existing ExposedSecret is respected",
+			policy: policy(v1alpha1.AdmissionEnforce, v1alpha1.ActionReportOnly),
+			existing: &v1alpha1.ExposedSecret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+				Spec:       v1alpha1.ExposedSecretSpec{Action: v1alpha1.ActionIgnore},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []client.Object{tt.policy}
+			if tt.existing != nil {
+				objs = append(objs, tt.existing)
+			}
+			cl, r := newReconciler(t, objs...)
+			v := NewConfigMapValidator(r, operator)
+			ctx := admission.NewContextWithRequest(logr.NewContext(t.Context(), logr.Discard()), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UserInfo: authenticationv1.UserInfo{Username: cmp.Or(tt.username, "user")},
+				},
+			})
+
+			warnings, err := v.ValidateCreate(ctx, cm.DeepCopy())
+			if tt.wantDenied {
+				require.True(t, apierrors.IsForbidden(err), "expected forbidden error, got %v", err)
+				require.Contains(t, err.Error(), "token (rules: [test-rule], severity: High)")
+				require.NotContains(t, err.Error(), secretValue)
+				require.NotContains(t, err.Error(), "excluded")
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, warnings, tt.wantWarnings)
+			for _, w := range warnings {
+				require.NotContains(t, w, secretValue)
+			}
+
+			var list v1alpha1.ExposedSecretList
+			require.NoError(t, cl.List(t.Context(), &list))
+			if tt.existing == nil {
+				require.Empty(t, list.Items, "admission must not create resources")
+			}
+		})
+	}
+}
+
+// newReconciler creates a [controllers.ConfigMapReconciler] backed by a fake client with the given objects.
+// It uses the [test.DefaultScanner] for scanning.
+func newReconciler(t *testing.T, objs ...client.Object) (client.Client, *controllers.ConfigMapReconciler) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.ExposedSecret{}, &v1alpha1.ScanPolicy{}).
+		Build()
+	factory.Set(t, test.DefaultScanner.Name(), test.DefaultScanner)
+	return cl, controllers.NewConfigMapReconciler(cl, scheme, &config.Config{}, nil)
+}
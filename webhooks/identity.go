@@ -0,0 +1,30 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Username returns the name the operator is authenticated as.
+// The ConfigMap webhooks admit the operator's own writes with it, so reverting
+// a remediation isn't denied or remediated again by the operator itself.
+func Username(ctx context.Context, c client.Client) (string, error) {
+	review := &authenticationv1.SelfSubjectReview{}
+	if err := c.Create(ctx, review); err != nil {
+		return "", fmt.Errorf("failed to review own identity: %w", err)
+	}
+	return review.Status.UserInfo.Username, nil
+}
+
+// isOperator reports whether the admission request in the context was sent by the operator.
+func isOperator(ctx context.Context, operator string) bool {
+	if operator == "" {
+		return false
+	}
+	req, err := admission.RequestFromContext(ctx)
+	return err == nil && req.UserInfo.Username == operator
+}
@@ -0,0 +1,76 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/lvlcn-t/secret-detection-operator/controllers"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/mutate--v1-configmap,mutating=true,failurePolicy=ignore,sideEffects=NoneOnDryRun,groups="",resources=configmaps,verbs=create;update,versions=v1,name=mconfigmap.secretdetection.lvlcn-t.dev,admissionReviewVersions=v1
+
+// decisionRemediated is recorded in [controllers.ConfigMapAdmissions] if secrets were moved on admission.
+const decisionRemediated = "remediated"
+
+var _ admission.Defaulter[*corev1.ConfigMap] = (*ConfigMapRemediator)(nil)
+
+// Remediator moves secrets out of ConfigMaps on admission, see [controllers.ConfigMapReconciler.Remediate].
+type Remediator interface {
+	Remediate(ctx context.Context, cm *corev1.ConfigMap, dryRun bool) (*controllers.Review, error)
+}
+
+// ConfigMapRemediator moves secrets from ConfigMaps to Secrets before the ConfigMaps are stored,
+// if the namespace's effective policy uses the Remediate admission mode.
+// Writes of the operator itself, e.g. reverting a remediation, are left unchanged.
+type ConfigMapRemediator struct {
+	remediator Remediator
+	operator   string
+}
+
+// NewConfigMapRemediator creates a new [ConfigMapRemediator].
+// The operator is the username of the operator, see [Username]; if empty, all writes are remediated.
+func NewConfigMapRemediator(r Remediator, operator string) *ConfigMapRemediator {
+	return &ConfigMapRemediator{remediator: r, operator: operator}
+}
+
+// SetupWithManager registers the mutating webhook for ConfigMaps with the manager.
+func (m *ConfigMapRemediator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &corev1.ConfigMap{}).
+		WithDefaulter(m).
+		Complete()
+}
+
+// Default remediates the secrets of a created or updated ConfigMap.
+// Failures are logged and leave the ConfigMap unchanged, so the
+// secrets are still reported or remediated by the reconciliation.
+func (m *ConfigMapRemediator) Default(ctx context.Context, cm *corev1.ConfigMap) error {
+	if isOperator(ctx, m.operator) {
+		return nil
+	}
+
+	log := logr.FromContextAsSlogLogger(ctx)
+	var dryRun bool
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.DryRun != nil {
+		dryRun = *req.DryRun
+	}
+
+	review, err := m.remediator.Remediate(ctx, cm, dryRun)
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to remediate ConfigMap on admission", "ConfigMap", cm.Name, "error", err)
+		return nil
+	}
+	if len(review.Findings) == 0 {
+		return nil
+	}
+
+	for i := range review.Findings {
+		log.InfoContext(ctx, "Remediated secret on admission", "ConfigMap", cm.Name, "location", review.Findings[i].Location, "dryRun", dryRun)
+	}
+	if !dryRun {
+		controllers.ConfigMapAdmissions.WithLabelValues(cm.Namespace, decisionRemediated).Inc()
+	}
+	return nil
+}
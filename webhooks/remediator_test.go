@@ -0,0 +1,151 @@
+package webhooks
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"github.com/lvlcn-t/secret-detection-operator/scanners"
+	"github.com/lvlcn-t/secret-detection-operator/test"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestConfigMapRemediator(t *testing.T) {
+	const secretValue = "my-secret"
+	name := v1alpha1.NewExposedSecretName(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}, "token")
+	policy := &v1alpha1.ScanPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pol"},
+		Spec: v1alpha1.ScanPolicySpec{
+			Action:        v1alpha1.ActionReportOnly,
+			MinSeverity:   scanners.SeverityLow,
+			Scanner:       test.DefaultScanner.Name(),
+			HashAlgorithm: v1alpha1.AlgorithmSHA256,
+			AdmissionMode: v1alpha1.AdmissionRemediate,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		mode          v1alpha1.AdmissionMode
+		cm            *corev1.ConfigMap
+		dryRun        bool
+		username      string
+		want          *corev1.ConfigMap
+		wantResources bool
+	}{
+		{
+			name: "secrets are moved before the ConfigMap is stored",
+			cm: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"token": secretValue, "plain": "hello"},
+			},
+			want: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", Annotations: map[string]string{v1alpha1.AnnotationExposedSecret: name}},
+				Data:       map[string]string{"plain": "hello"},
+			},
+			wantResources: true,
+		},
+		{
+			name: "dry run doesn't create resources",
+			cm: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"token": secretValue, "plain": "hello"},
+			},
+			dryRun: true,
+			want: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", Annotations: map[string]string{v1alpha1.AnnotationExposedSecret: name}},
+				Data:       map[string]string{"plain": "hello"},
+			},
+		},
+		{
+			name: "generated names are marked for remediation",
+			cm: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", GenerateName: "cm-"},
+				Data:       map[string]string{"token": secretValue, "plain": "hello"},
+			},
+			want: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", GenerateName: "cm-", Annotations: map[string]string{v1alpha1.AnnotationRemediate: "true"}},
+				Data:       map[string]string{"token": secretValue, "plain": "hello"},
+			},
+		},
+		{
+			name: "writes of the operator are left unchanged",
+			cm: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"token": secretValue, "plain": "hello"},
+			},
+			username: operator,
+			want: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"token": secretValue, "plain": "hello"},
+			},
+		},
+		{
+			name: "ConfigMaps without secrets are left unchanged",
+			cm: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"plain": "hello"},
+			},
+			want: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+				Data:       map[string]string{"plain": "hello"},
+			},
+		},
+		{
+			name: "remediation mark is removed if the policy doesn't remediate",
+			mode: v1alpha1.AdmissionWarn,
+			cm: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", Annotations: map[string]string{v1alpha1.AnnotationRemediate: "true"}},
+				Data:       map[string]string{"token": secretValue},
+			},
+			want: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", Annotations: map[string]string{}},
+				Data:       map[string]string{"token": secretValue},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pol := policy.DeepCopy()
+			if tt.mode != "" {
+				pol.Spec.AdmissionMode = tt.mode
+			}
+			cl, r := newReconciler(t, pol)
+			m := NewConfigMapRemediator(r, operator)
+			ctx := admission.NewContextWithRequest(logr.NewContext(t.Context(), logr.Discard()), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					DryRun:   ptr.To(tt.dryRun),
+					UserInfo: authenticationv1.UserInfo{Username: cmp.Or(tt.username, "user")},
+				},
+			})
+
+			cm := tt.cm.DeepCopy()
+			require.NoError(t, m.Default(ctx, cm))
+			require.Equal(t, tt.want, cm)
+
+			err := cl.Get(t.Context(), client.ObjectKey{Namespace: "ns", Name: name}, &corev1.Secret{})
+			es := &v1alpha1.ExposedSecret{}
+			esErr := cl.Get(t.Context(), client.ObjectKey{Namespace: "ns", Name: name}, es)
+			if !tt.wantResources {
+				require.True(t, apierrors.IsNotFound(err), "expected no Secret, got %v", err)
+				require.True(t, apierrors.IsNotFound(esErr), "expected no ExposedSecret, got %v", esErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, esErr)
+			require.Equal(t, v1alpha1.PhaseRemediated, es.Status.Phase)
+			require.Equal(t, v1alpha1.ActionAutoRemediate, es.Spec.Action)
+			require.Equal(t, &v1alpha1.SecretReference{Name: name}, es.Status.CreatedSecretRef)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"github.com/lvlcn-t/secret-detection-operator/scanners/gitleaks"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-secretdetection-lvlcn-t-dev-v1alpha1-scanpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=secretdetection.lvlcn-t.dev,resources=scanpolicies,verbs=create;update,versions=v1alpha1,name=vscanpolicy.secretdetection.lvlcn-t.dev,admissionReviewVersions=v1
+
+var _ admission.Validator[*v1alpha1.ScanPolicy] = (*ScanPolicyValidator)(nil)
+
+// ScanPolicyValidator validates [v1alpha1.ScanPolicy] resources on admission.
+type ScanPolicyValidator struct {
+	client.Reader
+	// singlePolicy rejects a ScanPolicy if another ScanPolicy exists in its namespace.
+	// Otherwise, a ScanPolicy is rejected if it can't be merged with the other ScanPolicies in its namespace.
+	singlePolicy bool
+	// pepper reports whether the operator has a pepper configured.
+	// Otherwise, a ScanPolicy using a keyed hash algorithm is rejected.
+	pepper bool
+}
+
+// NewScanPolicyValidator creates a new [ScanPolicyValidator].
+func NewScanPolicyValidator(r client.Reader, singlePolicy, pepper bool) *ScanPolicyValidator {
+	return &ScanPolicyValidator{Reader: r, singlePolicy: singlePolicy, pepper: pepper}
+}
+
+// SetupWithManager registers the validating webhook for ScanPolicies with the manager.
+func (v *ScanPolicyValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.ScanPolicy{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates a newly created ScanPolicy.
+func (v *ScanPolicyValidator) ValidateCreate(ctx context.Context, sp *v1alpha1.ScanPolicy) (admission.Warnings, error) {
+	return v.validate(ctx, sp)
+}
+
+// ValidateUpdate validates an updated ScanPolicy.
+func (v *ScanPolicyValidator) ValidateUpdate(ctx context.Context, _, sp *v1alpha1.ScanPolicy) (admission.Warnings, error) {
+	return v.validate(ctx, sp)
+}
+
+// ValidateDelete allows all deletions.
+func (v *ScanPolicyValidator) ValidateDelete(context.Context, *v1alpha1.ScanPolicy) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate validates the spec of the ScanPolicy and its compatibility with the other ScanPolicies in its namespace.
+func (v *ScanPolicyValidator) validate(ctx context.Context, sp *v1alpha1.ScanPolicy) (admission.Warnings, error) {
+	specPath := field.NewPath("spec")
+	errs := sp.Spec.GitleaksConfig.Validate(specPath.Child("gitleaksConfig"))
+	errs = append(errs, sp.Spec.ValidateExclusions(specPath)...)
+	if sp.Spec.HashAlgorithm.Keyed() && !v.pepper {
+		errs = append(errs, field.Forbidden(specPath.Child("hashAlgorithm"),
+			fmt.Sprintf("hash algorithm %s requires a pepper in the operator's configuration", sp.Spec.HashAlgorithm)))
+	}
+
+	var policies v1alpha1.ScanPolicyList
+	if err := v.List(ctx, &policies, client.InNamespace(sp.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list ScanPolicies: %w", err)
+	}
+	for i := range policies.Items {
+		other := &policies.Items[i]
+		if other.Name == sp.Name {
+			continue
+		}
+		if v.singlePolicy {
+			errs = append(errs, field.Forbidden(field.NewPath("metadata", "namespace"),
+				fmt.Sprintf("only one ScanPolicy is allowed per namespace, found %q", other.Name)))
+			continue
+		}
+		errs = append(errs, validateMergeable(specPath, &sp.Spec, other)...)
+	}
+
+	if len(errs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("ScanPolicy").GroupKind(), sp.Name, errs)
+}
+
+// validateMergeable reports the fields of the spec that conflict with another ScanPolicy in the same namespace.
+// Conflicting fields would silently be overridden when the policies are merged, see [v1alpha1.MergeScanPolicies].
+func validateMergeable(specPath *field.Path, spec *v1alpha1.ScanPolicySpec, other *v1alpha1.ScanPolicy) field.ErrorList {
+	var errs field.ErrorList
+	if spec.Scanner != "" && other.Spec.Scanner != "" && spec.Scanner.Normalize() != other.Spec.Scanner.Normalize() {
+		errs = append(errs, field.Invalid(specPath.Child("scanner"), spec.Scanner,
+			fmt.Sprintf("conflicts with scanner %q of ScanPolicy %q", other.Spec.Scanner, other.Name)))
+	}
+
+	if spec.GitleaksConfig == nil || other.Spec.GitleaksConfig == nil {
+		return errs
+	}
+	rules := map[string]gitleaks.Rule{}
+	for _, rule := range other.Spec.GitleaksConfig.Rules {
+		rules[rule.ID] = rule
+	}
+	for i, rule := range spec.GitleaksConfig.Rules {
+		if existing, ok := rules[rule.ID]; ok && !reflect.DeepEqual(existing, rule) {
+			errs = append(errs, field.Invalid(specPath.Child("gitleaksConfig", "rules").Index(i).Child("id"), rule.ID,
+				fmt.Sprintf("conflicts with a different rule of ScanPolicy %q", other.Name)))
+		}
+	}
+	return errs
+}
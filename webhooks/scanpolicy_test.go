@@ -0,0 +1,156 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/lvlcn-t/secret-detection-operator/apis/v1alpha1"
+	"github.com/lvlcn-t/secret-detection-operator/scanners/gitleaks"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScanPolicyValidator(t *testing.T) {
+	policy := func(name string, cfg *v1alpha1.GitleaksConfig) *v1alpha1.ScanPolicy {
+		return &v1alpha1.ScanPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec: v1alpha1.ScanPolicySpec{
+				Action:         v1alpha1.ActionReportOnly,
+				Scanner:        gitleaks.Name,
+				GitleaksConfig: cfg,
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		existing     []*v1alpha1.ScanPolicy
+		singlePolicy bool
+		pepper       bool
+		policy       *v1alpha1.ScanPolicy
+		wantFields   []string
+	}{
+		{
+			name: "valid rules",
+			policy: policy("p", &v1alpha1.GitleaksConfig{
+				Rules:     []gitleaks.Rule{{ID: "token", Regex: `token=(\w+)`, SecretGroup: 1, Entropy: "3.5"}},
+				Allowlist: []gitleaks.AllowlistRule{{Regex: "example"}},
+			}),
+		},
+		{
+			name: "invalid rules",
+			policy: policy("p", &v1alpha1.GitleaksConfig{
+				Rules: []gitleaks.Rule{
+					{ID: "a", Regex: "(unclosed"},
+					{ID: "a", Regex: "a+", Entropy: "high"},
+					{ID: "b", Regex: "b+", SecretGroup: 1},
+				},
+				Allowlist: []gitleaks.AllowlistRule{{Description: "empty"}, {Path: "[z-a]"}},
+			}),
+			wantFields: []string{
+				"spec.gitleaksConfig.rules[0].regex",
+				"spec.gitleaksConfig.rules[1].id",
+				"spec.gitleaksConfig.rules[1].entropy",
+				"spec.gitleaksConfig.rules[2].secretGroup",
+				"spec.gitleaksConfig.allowlist[0]",
+				"spec.gitleaksConfig.allowlist[1].path",
+			},
+		},
+		{
+			name: "invalid exclusions",
+			policy: &v1alpha1.ScanPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p"},
+				Spec: v1alpha1.ScanPolicySpec{
+					ExcludedKeys:       []string{"*.crt", "/(unclosed/", "/^public_.+$/"},
+					ExcludedConfigMaps: []string{"kube-root-ca.crt", "/[/"},
+					ExcludedConfigMapSelectors: []metav1.LabelSelector{
+						{MatchLabels: map[string]string{"app.kubernetes.io/managed-by": "Helm"}},
+						{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "a", Operator: "Unknown"}}},
+						{},
+					},
+				},
+			},
+			wantFields: []string{
+				"spec.excludedKeys[1]",
+				"spec.excludedConfigMaps[1]",
+				"spec.excludedConfigMapSelectors[1]",
+				"spec.excludedConfigMapSelectors[2]",
+			},
+		},
+		{
+			name:         "second policy with single policy per namespace",
+			existing:     []*v1alpha1.ScanPolicy{policy("other", nil)},
+			singlePolicy: true,
+			policy:       policy("p", nil),
+			wantFields:   []string{"metadata.namespace"},
+		},
+		{
+			name:         "updating the only policy with single policy per namespace",
+			existing:     []*v1alpha1.ScanPolicy{policy("p", nil)},
+			singlePolicy: true,
+			policy:       policy("p", nil),
+		},
+		{
+			name: "keyed hash algorithm without pepper",
+			policy: &v1alpha1.ScanPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p"},
+				Spec:       v1alpha1.ScanPolicySpec{HashAlgorithm: v1alpha1.AlgorithmHMACSHA256},
+			},
+			wantFields: []string{"spec.hashAlgorithm"},
+		},
+		{
+			name:   "keyed hash algorithm with pepper",
+			pepper: true,
+			policy: &v1alpha1.ScanPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p"},
+				Spec:       v1alpha1.ScanPolicySpec{HashAlgorithm: v1alpha1.AlgorithmHMACSHA512},
+			},
+		},
+		{
+			name: "mergeable policies",
+			existing: []*v1alpha1.ScanPolicy{policy("other", &v1alpha1.GitleaksConfig{
+				Rules: []gitleaks.Rule{{ID: "a", Regex: "a+"}},
+			})},
+			policy: policy("p", &v1alpha1.GitleaksConfig{
+				Rules: []gitleaks.Rule{{ID: "a", Regex: "a+"}, {ID: "b", Regex: "b+"}},
+			}),
+		},
+		{
+			name: "conflicting rule definitions",
+			existing: []*v1alpha1.ScanPolicy{policy("other", &v1alpha1.GitleaksConfig{
+				Rules: []gitleaks.Rule{{ID: "a", Regex: "a+"}},
+			})},
+			policy: policy("p", &v1alpha1.GitleaksConfig{
+				Rules: []gitleaks.Rule{{ID: "b", Regex: "b+"}, {ID: "a", Regex: "x+"}},
+			}),
+			wantFields: []string{"spec.gitleaksConfig.rules[1].id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, p := range tt.existing {
+				builder = builder.WithObjects(p)
+			}
+
+			v := NewScanPolicyValidator(builder.Build(), tt.singlePolicy, tt.pepper)
+			_, err := v.ValidateCreate(t.Context(), tt.policy)
+			if len(tt.wantFields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.True(t, apierrors.IsInvalid(err), "expected invalid error, got %v", err)
+			var fields []string
+			for _, cause := range err.(*apierrors.StatusError).Status().Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			require.Equal(t, tt.wantFields, fields)
+		})
+	}
+}